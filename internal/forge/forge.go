@@ -0,0 +1,217 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package forge talks to the APIs of Git hosting services to open change requests and mint access tokens.
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+	ProviderGitea  = "gitea"
+)
+
+// Providers lists the supported Git hosting services.
+var Providers = []string{ProviderGitHub, ProviderGitLab, ProviderGitea}
+
+// State is the state of a pull request.
+type State string
+
+const (
+	StateOpen   State = "open"
+	StateMerged State = "merged"
+	StateClosed State = "closed"
+)
+
+// PullRequest is a change request, called merge request by GitLab.
+type PullRequest struct {
+	Number int
+	URL    string
+	State  State
+}
+
+// PullRequestOptions describes the pull request to open.
+type PullRequestOptions struct {
+	Title       string
+	Description string
+	HeadBranch  string
+	BaseBranch  string
+}
+
+// Forge opens pull requests on a Git repository and reports their state.
+type Forge interface {
+	CreatePullRequest(ctx context.Context, opts PullRequestOptions) (*PullRequest, error)
+	GetPullRequest(ctx context.Context, number int) (*PullRequest, error)
+}
+
+// Options configures the client of a Git hosting service.
+type Options struct {
+	// Provider is one of Providers.
+	Provider string
+	// APIURL is the base URL of the API, it is derived from the repository URL when empty.
+	APIURL string
+	// Token is used to authenticate to the API.
+	Token string
+	// RepositoryURL is the clone URL of the repository.
+	RepositoryURL *url.URL
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// New returns the Forge implementation for the configured provider.
+func New(opts Options) (Forge, error) {
+	repoPath, err := repositoryPath(opts.RepositoryURL)
+	if err != nil {
+		return nil, err
+	}
+	c := &client{
+		httpClient: opts.HTTPClient,
+		token:      opts.Token,
+	}
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+
+	switch opts.Provider {
+	case ProviderGitHub:
+		c.baseURL = opts.APIURL
+		if c.baseURL == "" {
+			c.baseURL = "https://api.github.com"
+			if opts.RepositoryURL.Hostname() != "github.com" {
+				c.baseURL = fmt.Sprintf("https://%s/api/v3", opts.RepositoryURL.Hostname())
+			}
+		}
+		c.authHeader, c.authValue = "Authorization", "Bearer "+opts.Token
+		return &gitHub{client: c, repository: repoPath}, nil
+	case ProviderGitLab:
+		c.baseURL = opts.APIURL
+		if c.baseURL == "" {
+			c.baseURL = fmt.Sprintf("https://%s/api/v4", opts.RepositoryURL.Hostname())
+		}
+		c.authHeader, c.authValue = "PRIVATE-TOKEN", opts.Token
+		return &gitLab{client: c, project: repoPath}, nil
+	case ProviderGitea:
+		c.baseURL = opts.APIURL
+		if c.baseURL == "" {
+			c.baseURL = fmt.Sprintf("%s://%s/api/v1", apiScheme(opts.RepositoryURL), opts.RepositoryURL.Host)
+		}
+		c.authHeader, c.authValue = "Authorization", "token "+opts.Token
+		return &gitea{client: c, repository: repoPath}, nil
+	default:
+		return nil, fmt.Errorf("unsupported provider %q, expected one of %v", opts.Provider, Providers)
+	}
+}
+
+// WaitForMerge polls the pull request until it is merged. An error is returned
+// when the pull request is closed without being merged or the context is done.
+func WaitForMerge(ctx context.Context, f Forge, number int, interval time.Duration) (*PullRequest, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		pr, err := f.GetPullRequest(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		switch pr.State {
+		case StateMerged:
+			return pr, nil
+		case StateClosed:
+			return nil, fmt.Errorf("pull request %s was closed without being merged", pr.URL)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timeout waiting for pull request %s to be merged: %w", pr.URL, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// repositoryPath returns the path of the repository without leading slash and .git suffix.
+func repositoryPath(u *url.URL) (string, error) {
+	if u == nil {
+		return "", fmt.Errorf("repository URL is empty")
+	}
+	p := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if !strings.Contains(p, "/") {
+		return "", fmt.Errorf("could not get owner and name of repository from URL %q", u.Redacted())
+	}
+	return p, nil
+}
+
+// apiScheme returns the scheme serving the API of self-hosted services, SSH remotes are assumed to use HTTPS.
+func apiScheme(u *url.URL) string {
+	if u.Scheme == "http" {
+		return "http"
+	}
+	return "https"
+}
+
+type client struct {
+	httpClient *http.Client
+	baseURL    string
+	authHeader string
+	authValue  string
+	token      string
+}
+
+// do sends the request with the JSON encoded body and decodes the JSON response into out.
+func (c *client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.baseURL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set(c.authHeader, c.authValue)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned %s: %s", method, req.URL.Redacted(), resp.Status, strings.TrimSpace(string(b)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(b, out)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDefaultAPIURL(t *testing.T) {
+	tests := []struct {
+		provider string
+		repoURL  string
+		expected string
+	}{
+		{ProviderGitHub, "https://github.com/fluxcd/flux2.git", "https://api.github.com"},
+		{ProviderGitHub, "ssh://git@github.example.com/fluxcd/flux2", "https://github.example.com/api/v3"},
+		{ProviderGitLab, "https://gitlab.com/group/sub/repo.git", "https://gitlab.com/api/v4"},
+		{ProviderGitea, "http://gitea:3000/org/repo.git", "http://gitea:3000/api/v1"},
+		{ProviderGitea, "ssh://git@codeberg.org/org/repo", "https://codeberg.org/api/v1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider+" "+tt.repoURL, func(t *testing.T) {
+			u, err := url.Parse(tt.repoURL)
+			require.NoError(t, err)
+			f, err := New(Options{Provider: tt.provider, RepositoryURL: u})
+			require.NoError(t, err)
+			switch f := f.(type) {
+			case *gitHub:
+				assert.Equal(t, tt.expected, f.baseURL)
+			case *gitLab:
+				assert.Equal(t, tt.expected, f.baseURL)
+			case *gitea:
+				assert.Equal(t, tt.expected, f.baseURL)
+			}
+		})
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	u, err := url.Parse("https://github.com/fluxcd")
+	require.NoError(t, err)
+	_, err = New(Options{Provider: ProviderGitHub, RepositoryURL: u})
+	assert.ErrorContains(t, err, "could not get owner and name")
+
+	u, err = url.Parse("https://github.com/fluxcd/flux2")
+	require.NoError(t, err)
+	_, err = New(Options{Provider: "bitbucket", RepositoryURL: u})
+	assert.ErrorContains(t, err, "unsupported provider")
+}
+
+func TestGitHubPullRequest(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /repos/fluxcd/fleet/pulls", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var in pullRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		assert.Equal(t, pullRequest{Title: "Update Flux", Body: "body", Head: "flux-bootstrap-1", Base: "main"}, in)
+		_, _ = w.Write([]byte(`{"number": 7, "html_url": "https://github.com/fluxcd/fleet/pull/7", "state": "open"}`))
+	})
+	mux.HandleFunc("GET /repos/fluxcd/fleet/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"number": 7, "html_url": "https://github.com/fluxcd/fleet/pull/7", "state": "closed", "merged": true}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	f := newTestForge(t, ProviderGitHub, srv.URL)
+	pr, err := f.CreatePullRequest(context.TODO(), PullRequestOptions{Title: "Update Flux", Description: "body", HeadBranch: "flux-bootstrap-1", BaseBranch: "main"})
+	require.NoError(t, err)
+	assert.Equal(t, &PullRequest{Number: 7, URL: "https://github.com/fluxcd/fleet/pull/7", State: StateOpen}, pr)
+
+	pr, err = f.GetPullRequest(context.TODO(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, StateMerged, pr.State)
+}
+
+func TestGitLabMergeRequest(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /projects/{project}/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "fluxcd/fleet", r.PathValue("project"))
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		var in map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		assert.Equal(t, "flux-bootstrap-1", in["source_branch"])
+		assert.Equal(t, "main", in["target_branch"])
+		_, _ = w.Write([]byte(`{"iid": 3, "web_url": "https://gitlab.com/fluxcd/fleet/-/merge_requests/3", "state": "opened"}`))
+	})
+	mux.HandleFunc("GET /projects/{project}/merge_requests/3", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"iid": 3, "web_url": "https://gitlab.com/fluxcd/fleet/-/merge_requests/3", "state": "closed"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	f := newTestForge(t, ProviderGitLab, srv.URL)
+	pr, err := f.CreatePullRequest(context.TODO(), PullRequestOptions{Title: "Update Flux", HeadBranch: "flux-bootstrap-1", BaseBranch: "main"})
+	require.NoError(t, err)
+	assert.Equal(t, &PullRequest{Number: 3, URL: "https://gitlab.com/fluxcd/fleet/-/merge_requests/3", State: StateOpen}, pr)
+
+	_, err = WaitForMerge(context.TODO(), f, 3, time.Millisecond)
+	assert.ErrorContains(t, err, "closed without being merged")
+}
+
+func TestGitLabWaitForMergeLocked(t *testing.T) {
+	calls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /projects/{project}/merge_requests/3", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			_, _ = w.Write([]byte(`{"iid": 3, "state": "locked"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"iid": 3, "state": "merged"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	f := newTestForge(t, ProviderGitLab, srv.URL)
+	pr, err := WaitForMerge(context.TODO(), f, 3, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, StateMerged, pr.State)
+	assert.Equal(t, 3, calls)
+}
+
+func TestGiteaWaitForMerge(t *testing.T) {
+	calls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/fluxcd/fleet/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		calls++
+		if calls < 3 {
+			_, _ = w.Write([]byte(`{"number": 1, "state": "open"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"number": 1, "state": "closed", "merged": true}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	f := newTestForge(t, ProviderGitea, srv.URL)
+	pr, err := WaitForMerge(context.TODO(), f, 1, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, StateMerged, pr.State)
+	assert.Equal(t, 3, calls)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	calls = 0
+	_, err = WaitForMerge(ctx, f, 1, time.Millisecond)
+	assert.Error(t, err)
+}
+
+func TestErrorResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Validation Failed"}`, http.StatusUnprocessableEntity)
+	}))
+	defer srv.Close()
+
+	f := newTestForge(t, ProviderGitHub, srv.URL)
+	_, err := f.CreatePullRequest(context.TODO(), PullRequestOptions{})
+	assert.ErrorContains(t, err, "422 Unprocessable Entity")
+	assert.ErrorContains(t, err, "Validation Failed")
+}
+
+func newTestForge(t *testing.T, provider, apiURL string) Forge {
+	t.Helper()
+	u, err := url.Parse("https://example.com/fluxcd/fleet.git")
+	require.NoError(t, err)
+	f, err := New(Options{Provider: provider, APIURL: apiURL, Token: "secret", RepositoryURL: u})
+	require.NoError(t, err)
+	return f
+}
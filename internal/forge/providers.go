@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// pull is the pull request representation shared by the GitHub and Gitea APIs.
+type pull struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	State   string `json:"state"`
+	Merged  bool   `json:"merged"`
+}
+
+func (p pull) toPullRequest() *PullRequest {
+	pr := &PullRequest{Number: p.Number, URL: p.HTMLURL, State: StateOpen}
+	switch {
+	case p.Merged:
+		pr.State = StateMerged
+	case p.State == "closed":
+		pr.State = StateClosed
+	}
+	return pr
+}
+
+type pullRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+}
+
+type gitHub struct {
+	*client
+	repository string
+}
+
+func (g *gitHub) CreatePullRequest(ctx context.Context, opts PullRequestOptions) (*PullRequest, error) {
+	var out pull
+	in := pullRequest{Title: opts.Title, Body: opts.Description, Head: opts.HeadBranch, Base: opts.BaseBranch}
+	if err := g.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/pulls", g.repository), in, &out); err != nil {
+		return nil, fmt.Errorf("could not create pull request: %w", err)
+	}
+	return out.toPullRequest(), nil
+}
+
+func (g *gitHub) GetPullRequest(ctx context.Context, number int) (*PullRequest, error) {
+	var out pull
+	if err := g.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/pulls/%d", g.repository, number), nil, &out); err != nil {
+		return nil, fmt.Errorf("could not get pull request: %w", err)
+	}
+	return out.toPullRequest(), nil
+}
+
+type gitea struct {
+	*client
+	repository string
+}
+
+func (g *gitea) CreatePullRequest(ctx context.Context, opts PullRequestOptions) (*PullRequest, error) {
+	var out pull
+	in := pullRequest{Title: opts.Title, Body: opts.Description, Head: opts.HeadBranch, Base: opts.BaseBranch}
+	if err := g.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/pulls", g.repository), in, &out); err != nil {
+		return nil, fmt.Errorf("could not create pull request: %w", err)
+	}
+	return out.toPullRequest(), nil
+}
+
+func (g *gitea) GetPullRequest(ctx context.Context, number int) (*PullRequest, error) {
+	var out pull
+	if err := g.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/pulls/%d", g.repository, number), nil, &out); err != nil {
+		return nil, fmt.Errorf("could not get pull request: %w", err)
+	}
+	return out.toPullRequest(), nil
+}
+
+type mergeRequest struct {
+	IID    int    `json:"iid"`
+	WebURL string `json:"web_url"`
+	State  string `json:"state"`
+}
+
+func (m mergeRequest) toPullRequest() *PullRequest {
+	pr := &PullRequest{Number: m.IID, URL: m.WebURL, State: StateOpen}
+	// GitLab reports a merge request as locked while it is being merged, which is kept open.
+	switch m.State {
+	case "merged":
+		pr.State = StateMerged
+	case "closed":
+		pr.State = StateClosed
+	}
+	return pr
+}
+
+type gitLab struct {
+	*client
+	project string
+}
+
+func (g *gitLab) CreatePullRequest(ctx context.Context, opts PullRequestOptions) (*PullRequest, error) {
+	var out mergeRequest
+	in := map[string]string{
+		"title":         opts.Title,
+		"description":   opts.Description,
+		"source_branch": opts.HeadBranch,
+		"target_branch": opts.BaseBranch,
+	}
+	if err := g.do(ctx, http.MethodPost, fmt.Sprintf("/projects/%s/merge_requests", url.PathEscape(g.project)), in, &out); err != nil {
+		return nil, fmt.Errorf("could not create merge request: %w", err)
+	}
+	return out.toPullRequest(), nil
+}
+
+func (g *gitLab) GetPullRequest(ctx context.Context, number int) (*PullRequest, error) {
+	var out mergeRequest
+	if err := g.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%s/merge_requests/%d", url.PathEscape(g.project), number), nil, &out); err != nil {
+		return nil, fmt.Errorf("could not get merge request: %w", err)
+	}
+	return out.toPullRequest(), nil
+}
@@ -22,15 +22,40 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
+var _ basetypes.StringTypable = DurationType{}
+
 type DurationType struct {
 	basetypes.StringType
 }
 
+func (t DurationType) Equal(o attr.Type) bool {
+	_, ok := o.(DurationType)
+	return ok
+}
+
+func (t DurationType) String() string {
+	return "types.DurationType"
+}
+
+// ValueFromString parses the duration of known values. Invalid durations are reported by ValidateAttribute.
+func (t DurationType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	v := Duration{StringValue: in}
+	if in.IsNull() || in.IsUnknown() {
+		return v, nil
+	}
+	if d, err := time.ParseDuration(in.ValueString()); err == nil {
+		v.duration = d
+	}
+	return v, nil
+}
+
 func (t DurationType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
 	val, err := t.StringType.ValueFromTerraform(ctx, in)
 	if err != nil {
@@ -40,24 +65,74 @@ func (t DurationType) ValueFromTerraform(ctx context.Context, in tftypes.Value)
 	if !ok {
 		return nil, fmt.Errorf("value of unexpected type")
 	}
-	if strVal.ValueString() == "" {
-		return DurationNull(), nil
+	v, diags := t.ValueFromString(ctx, strVal)
+	if diags.HasError() {
+		return nil, fmt.Errorf("could not convert value: %v", diags)
 	}
-	d, err := time.ParseDuration(strVal.ValueString())
-	if err != nil {
-		return nil, fmt.Errorf("could not parse duration: %w", err)
-	}
-	return Duration{
-		StringValue: strVal,
-		duration:    d,
-	}, nil
+	return v, nil
+}
+
+func (t DurationType) ValueType(ctx context.Context) attr.Value {
+	return Duration{}
 }
 
+var (
+	_ basetypes.StringValuableWithSemanticEquals = Duration{}
+	_ xattr.ValidateableAttribute                = Duration{}
+)
+
 type Duration struct {
 	basetypes.StringValue
 	duration time.Duration
 }
 
+func (v Duration) Type(ctx context.Context) attr.Type {
+	return DurationType{}
+}
+
+func (v Duration) Equal(o attr.Value) bool {
+	other, ok := o.(Duration)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+// StringSemanticEquals compares the parsed durations, so that e.g. 60s is equal to 1m0s.
+func (v Duration) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(Duration)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got value type %T. Please report this to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+	oldDuration, err := time.ParseDuration(v.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	newDuration, err := time.ParseDuration(newValue.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	return oldDuration == newDuration, diags
+}
+
+func (v Duration) ValidateAttribute(ctx context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+	if _, err := time.ParseDuration(v.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			fmt.Sprintf("could not parse duration: %s", err),
+		)
+	}
+}
+
 func (v Duration) ValueDuration() time.Duration {
 	return v.duration
 }
@@ -74,6 +149,7 @@ func DurationUnknown() Duration {
 	}
 }
 
+// DurationValue returns the duration in its normalized format, e.g. 1m0s.
 func DurationValue(value time.Duration) Duration {
 	return Duration{
 		StringValue: types.StringValue(value.String()),
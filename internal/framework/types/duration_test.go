@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func durationFromTerraform(t *testing.T, value string) Duration {
+	t.Helper()
+	v, err := DurationType{}.ValueFromTerraform(context.Background(), tftypes.NewValue(tftypes.String, value))
+	require.NoError(t, err)
+	return v.(Duration)
+}
+
+func TestDurationStringSemanticEquals(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldValue string
+		newValue string
+		expected bool
+	}{
+		{
+			name:     "identical",
+			oldValue: "1m0s",
+			newValue: "1m0s",
+			expected: true,
+		},
+		{
+			name:     "different format",
+			oldValue: "60s",
+			newValue: "1m0s",
+			expected: true,
+		},
+		{
+			name:     "different duration",
+			oldValue: "1m",
+			newValue: "10m",
+			expected: false,
+		},
+		{
+			name:     "invalid duration",
+			oldValue: "one minute",
+			newValue: "1m",
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			equal, diags := durationFromTerraform(t, tt.oldValue).StringSemanticEquals(context.Background(), durationFromTerraform(t, tt.newValue))
+			require.False(t, diags.HasError())
+			require.Equal(t, tt.expected, equal)
+		})
+	}
+
+	_, diags := DurationValue(time.Minute).StringSemanticEquals(context.Background(), URLNull())
+	require.True(t, diags.HasError())
+}
+
+func TestDurationTypeValueFromTerraform(t *testing.T) {
+	ctx := context.Background()
+
+	v := durationFromTerraform(t, "60s")
+	require.Equal(t, time.Minute, v.ValueDuration())
+	require.Equal(t, "60s", v.ValueString())
+	require.Equal(t, DurationType{}, v.Type(ctx))
+	require.True(t, v.Equal(durationFromTerraform(t, "60s")))
+	require.False(t, v.Equal(DurationValue(time.Minute)))
+
+	val, err := DurationType{}.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, tftypes.UnknownValue))
+	require.NoError(t, err)
+	require.Equal(t, DurationUnknown(), val)
+
+	val, err = DurationType{}.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, nil))
+	require.NoError(t, err)
+	require.Equal(t, DurationNull(), val)
+
+	require.IsType(t, Duration{}, DurationType{}.ValueType(ctx))
+	require.True(t, DurationType{}.Equal(DurationType{}))
+	require.False(t, DurationType{}.Equal(URLType{}))
+}
+
+func TestDurationValidateAttribute(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     Duration
+		expectErr bool
+	}{
+		{
+			name:  "valid",
+			value: durationFromTerraform(t, "1h30m"),
+		},
+		{
+			name:      "invalid",
+			value:     durationFromTerraform(t, "one minute"),
+			expectErr: true,
+		},
+		{
+			name:  "null",
+			value: DurationNull(),
+		},
+		{
+			name:  "unknown",
+			value: DurationUnknown(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &xattr.ValidateAttributeResponse{}
+			tt.value.ValidateAttribute(context.Background(), xattr.ValidateAttributeRequest{Path: path.Root("interval")}, resp)
+			require.Equal(t, tt.expectErr, resp.Diagnostics.HasError())
+		})
+	}
+}
+
+func TestDurationValue(t *testing.T) {
+	v := DurationValue(90 * time.Second)
+	require.Equal(t, "1m30s", v.ValueString())
+	require.Equal(t, 90*time.Second, v.ValueDuration())
+}
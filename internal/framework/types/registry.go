@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var _ basetypes.StringTypable = RegistryType{}
+
+// RegistryType is the type of container registry repositories such as ghcr.io/fluxcd, which image
+// names are appended to. Unlike URLType it has no semantic equality, as the value is used verbatim
+// as the prefix of the images.
+type RegistryType struct {
+	basetypes.StringType
+}
+
+func (t RegistryType) Equal(o attr.Type) bool {
+	_, ok := o.(RegistryType)
+	return ok
+}
+
+func (t RegistryType) String() string {
+	return "types.RegistryType"
+}
+
+func (t RegistryType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return Registry{StringValue: in}, nil
+}
+
+func (t RegistryType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	val, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	strVal, ok := val.(types.String)
+	if !ok {
+		return nil, fmt.Errorf("value of unexpected type")
+	}
+	return Registry{StringValue: strVal}, nil
+}
+
+func (t RegistryType) ValueType(ctx context.Context) attr.Value {
+	return Registry{}
+}
+
+var _ xattr.ValidateableAttribute = Registry{}
+
+type Registry struct {
+	basetypes.StringValue
+}
+
+func (v Registry) Type(ctx context.Context) attr.Type {
+	return RegistryType{}
+}
+
+func (v Registry) Equal(o attr.Value) bool {
+	other, ok := o.(Registry)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+// ValidateAttribute rejects values with a scheme or trailing slash, which would result in invalid
+// image references, and values without a valid registry host.
+func (v Registry) ValidateAttribute(ctx context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+	if err := validateRegistry(v.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid registry",
+			fmt.Sprintf("expected a registry host optionally followed by a repository path, e.g. ghcr.io/fluxcd: %s", err),
+		)
+	}
+}
+
+func validateRegistry(s string) error {
+	if strings.Contains(s, "://") {
+		return fmt.Errorf("%s must not contain a scheme", s)
+	}
+	if strings.HasSuffix(s, "/") {
+		return fmt.Errorf("%s must not end with a slash", s)
+	}
+	host, _, _ := strings.Cut(s, "/")
+	if _, err := name.NewRegistry(host, name.StrictValidation); err != nil {
+		return err
+	}
+	return nil
+}
+
+func RegistryNull() Registry {
+	return Registry{
+		StringValue: types.StringNull(),
+	}
+}
+
+func RegistryUnknown() Registry {
+	return Registry{
+		StringValue: types.StringUnknown(),
+	}
+}
+
+func RegistryValue(value string) Registry {
+	return Registry{
+		StringValue: types.StringValue(value),
+	}
+}
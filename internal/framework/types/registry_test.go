@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryTypeValueFromTerraform(t *testing.T) {
+	ctx := context.Background()
+	val, err := RegistryType{}.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, "ghcr.io/fluxcd"))
+	require.NoError(t, err)
+	require.Equal(t, RegistryValue("ghcr.io/fluxcd"), val)
+	require.Equal(t, RegistryType{}, val.Type(ctx))
+	require.False(t, val.Equal(RegistryValue("ghcr.io/fluxcd/")))
+
+	val, err = RegistryType{}.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, tftypes.UnknownValue))
+	require.NoError(t, err)
+	require.Equal(t, RegistryUnknown(), val)
+
+	// Registries are compared verbatim, as they are the prefix of the rendered images.
+	_, ok := val.(basetypes.StringValuableWithSemanticEquals)
+	require.False(t, ok)
+}
+
+func TestRegistryValidateAttribute(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     Registry
+		expectErr bool
+	}{
+		{
+			name:  "host and repository",
+			value: RegistryValue("ghcr.io/fluxcd"),
+		},
+		{
+			name:  "host with port",
+			value: RegistryValue("registry.example.com:5000/fluxcd/flux2"),
+		},
+		{
+			name:  "host only",
+			value: RegistryValue("registry.example.com"),
+		},
+		{
+			name:      "scheme",
+			value:     RegistryValue("https://ghcr.io/fluxcd"),
+			expectErr: true,
+		},
+		{
+			name:      "trailing slash",
+			value:     RegistryValue("ghcr.io/fluxcd/"),
+			expectErr: true,
+		},
+		{
+			name:      "invalid host",
+			value:     RegistryValue("ghcr io/fluxcd"),
+			expectErr: true,
+		},
+		{
+			name:  "null",
+			value: RegistryNull(),
+		},
+		{
+			name:  "unknown",
+			value: RegistryUnknown(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &xattr.ValidateAttributeResponse{}
+			tt.value.ValidateAttribute(context.Background(), xattr.ValidateAttributeRequest{Path: path.Root("registry")}, resp)
+			require.Equal(t, tt.expectErr, resp.Diagnostics.HasError())
+		})
+	}
+}
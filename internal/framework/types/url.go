@@ -20,17 +20,41 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
+var _ basetypes.StringTypable = URLType{}
+
 type URLType struct {
 	basetypes.StringType
 }
 
+func (t URLType) Equal(o attr.Type) bool {
+	_, ok := o.(URLType)
+	return ok
+}
+
+func (t URLType) String() string {
+	return "types.URLType"
+}
+
+// ValueFromString parses the url, which is empty for null and unknown values. Invalid urls are
+// reported by ValidateAttribute.
+func (t URLType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	v := URL{StringValue: in}
+	if u, err := url.Parse(in.ValueString()); err == nil {
+		v.url = u
+	}
+	return v, nil
+}
+
 func (t URLType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
 	val, err := t.StringType.ValueFromTerraform(ctx, in)
 	if err != nil {
@@ -40,25 +64,103 @@ func (t URLType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr
 	if !ok {
 		return nil, fmt.Errorf("value of unexpected type")
 	}
-	u, err := url.Parse(strVal.ValueString())
-	if err != nil {
-		return nil, err
+	v, diags := t.ValueFromString(ctx, strVal)
+	if diags.HasError() {
+		return nil, fmt.Errorf("could not convert value: %v", diags)
 	}
-	return URL{
-		StringValue: strVal,
-		url:         u,
-	}, nil
+	return v, nil
+}
+
+func (t URLType) ValueType(ctx context.Context) attr.Value {
+	return URL{}
 }
 
+var (
+	_ basetypes.StringValuableWithSemanticEquals = URL{}
+	_ xattr.ValidateableAttribute                = URL{}
+)
+
 type URL struct {
 	basetypes.StringValue
 	url *url.URL
 }
 
+func (v URL) Type(ctx context.Context) attr.Type {
+	return URLType{}
+}
+
+func (v URL) Equal(o attr.Value) bool {
+	other, ok := o.(URL)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+// StringSemanticEquals compares the normalized urls, so that e.g. https://github.com/fluxcd/fleet/ is
+// equal to github.com/fluxcd/fleet, as urls without scheme default to https. Container registries use
+// RegistryType instead, as their value is used verbatim.
+func (v URL) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(URL)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got value type %T. Please report this to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+	oldURL, err := normalizeURL(v.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	newURL, err := normalizeURL(newValue.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	return oldURL == newURL, diags
+}
+
+func (v URL) ValidateAttribute(ctx context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+	if _, err := url.Parse(v.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid URL",
+			fmt.Sprintf("could not parse url: %s", err),
+		)
+	}
+}
+
 func (v URL) ValueURL() *url.URL {
 	return v.url
 }
 
+// normalizeURL defaults the scheme to https and lowercases the scheme and host. The trailing slash of
+// the path and the default port of the scheme are removed.
+func normalizeURL(s string) (string, error) {
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", err
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	switch u.Scheme {
+	case "https":
+		u.Host = strings.TrimSuffix(u.Host, ":443")
+	case "http":
+		u.Host = strings.TrimSuffix(u.Host, ":80")
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+	return u.String(), nil
+}
+
 func URLNull() URL {
 	return URL{
 		StringValue: types.StringNull(),
@@ -71,6 +173,7 @@ func URLUnknown() URL {
 	}
 }
 
+// URLValue returns the url in the format of url.URL.String.
 func URLValue(value *url.URL) URL {
 	return URL{
 		StringValue: types.StringValue(value.String()),
@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func urlFromTerraform(t *testing.T, value string) URL {
+	t.Helper()
+	v, err := URLType{}.ValueFromTerraform(context.Background(), tftypes.NewValue(tftypes.String, value))
+	require.NoError(t, err)
+	return v.(URL)
+}
+
+func TestURLStringSemanticEquals(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldValue string
+		newValue string
+		expected bool
+	}{
+		{
+			name:     "identical",
+			oldValue: "https://github.com/fluxcd/fleet.git",
+			newValue: "https://github.com/fluxcd/fleet.git",
+			expected: true,
+		},
+		{
+			name:     "without scheme",
+			oldValue: "https://ghcr.io/fluxcd",
+			newValue: "ghcr.io/fluxcd",
+			expected: true,
+		},
+		{
+			name:     "trailing slash, case and default port",
+			oldValue: "HTTPS://GHCR.io:443/fluxcd/",
+			newValue: "ghcr.io/fluxcd",
+			expected: true,
+		},
+		{
+			name:     "different scheme",
+			oldValue: "http://ghcr.io/fluxcd",
+			newValue: "ghcr.io/fluxcd",
+			expected: false,
+		},
+		{
+			name:     "different port",
+			oldValue: "https://registry.example.com:5000/fluxcd",
+			newValue: "registry.example.com/fluxcd",
+			expected: false,
+		},
+		{
+			name:     "different path",
+			oldValue: "ghcr.io/fluxcd",
+			newValue: "ghcr.io/fluxcd/flux2",
+			expected: false,
+		},
+		{
+			name:     "ssh user",
+			oldValue: "ssh://git@github.com/fluxcd/fleet.git",
+			newValue: "ssh://github.com/fluxcd/fleet.git",
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			equal, diags := urlFromTerraform(t, tt.oldValue).StringSemanticEquals(context.Background(), urlFromTerraform(t, tt.newValue))
+			require.False(t, diags.HasError())
+			require.Equal(t, tt.expected, equal)
+		})
+	}
+
+	_, diags := URLNull().StringSemanticEquals(context.Background(), DurationNull())
+	require.True(t, diags.HasError())
+}
+
+func TestURLTypeValueFromTerraform(t *testing.T) {
+	ctx := context.Background()
+
+	v := urlFromTerraform(t, "https://github.com/fluxcd/fleet.git")
+	require.Equal(t, "github.com", v.ValueURL().Host)
+	require.Equal(t, URLType{}, v.Type(ctx))
+	require.True(t, v.Equal(urlFromTerraform(t, "https://github.com/fluxcd/fleet.git")))
+	require.False(t, v.Equal(urlFromTerraform(t, "https://github.com/fluxcd/flux2.git")))
+
+	v = urlFromTerraform(t, "http://[::1")
+	require.Nil(t, v.ValueURL())
+
+	val, err := URLType{}.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, tftypes.UnknownValue))
+	require.NoError(t, err)
+	require.True(t, val.IsUnknown())
+
+	require.IsType(t, URL{}, URLType{}.ValueType(ctx))
+	require.True(t, URLType{}.Equal(URLType{}))
+	require.False(t, URLType{}.Equal(DurationType{}))
+}
+
+func TestURLValidateAttribute(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     URL
+		expectErr bool
+	}{
+		{
+			name:  "valid",
+			value: urlFromTerraform(t, "ssh://git@github.com/fluxcd/fleet.git"),
+		},
+		{
+			name:      "invalid",
+			value:     urlFromTerraform(t, "http://[::1"),
+			expectErr: true,
+		},
+		{
+			name:  "null",
+			value: URLNull(),
+		},
+		{
+			name:  "unknown",
+			value: URLUnknown(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &xattr.ValidateAttributeResponse{}
+			tt.value.ValidateAttribute(context.Background(), xattr.ValidateAttributeRequest{Path: path.Root("url")}, resp)
+			require.Equal(t, tt.expectErr, resp.Diagnostics.HasError())
+		})
+	}
+}
+
+func TestURLValue(t *testing.T) {
+	u, err := url.Parse("ghcr.io/fluxcd")
+	require.NoError(t, err)
+	v := URLValue(u)
+	require.Equal(t, "ghcr.io/fluxcd", v.ValueString())
+	require.Equal(t, u, v.ValueURL())
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+var _ basetypes.StringTypable = YAMLType{}
+
+// YAMLType is the type of multi-document YAML strings, which are semantically equal when their
+// documents are equal regardless of formatting, comments, key order and empty documents.
+type YAMLType struct {
+	basetypes.StringType
+}
+
+func (t YAMLType) Equal(o attr.Type) bool {
+	_, ok := o.(YAMLType)
+	return ok
+}
+
+func (t YAMLType) String() string {
+	return "types.YAMLType"
+}
+
+func (t YAMLType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return YAML{StringValue: in}, nil
+}
+
+func (t YAMLType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	val, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	strVal, ok := val.(types.String)
+	if !ok {
+		return nil, fmt.Errorf("value of unexpected type")
+	}
+	return YAML{StringValue: strVal}, nil
+}
+
+func (t YAMLType) ValueType(ctx context.Context) attr.Value {
+	return YAML{}
+}
+
+var _ basetypes.StringValuableWithSemanticEquals = YAML{}
+
+type YAML struct {
+	basetypes.StringValue
+}
+
+func (v YAML) Type(ctx context.Context) attr.Type {
+	return YAMLType{}
+}
+
+func (v YAML) Equal(o attr.Value) bool {
+	other, ok := o.(YAML)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+// StringSemanticEquals compares the parsed documents of the values. Values which cannot be
+// parsed are only equal to the same string.
+func (v YAML) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(YAML)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got value type %T. Please report this to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+	if v.ValueString() == newValue.ValueString() {
+		return true, diags
+	}
+	oldDocs, err := parseYAMLDocuments(v.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	newDocs, err := parseYAMLDocuments(newValue.ValueString())
+	if err != nil {
+		return false, diags
+	}
+	return reflect.DeepEqual(oldDocs, newDocs), diags
+}
+
+func parseYAMLDocuments(content string) ([]interface{}, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(content)))
+	docs := []interface{}{}
+	for {
+		b, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var doc interface{}
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			return nil, err
+		}
+		// Empty documents and documents only containing comments are skipped.
+		if doc == nil {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+func YAMLNull() YAML {
+	return YAML{
+		StringValue: types.StringNull(),
+	}
+}
+
+func YAMLUnknown() YAML {
+	return YAML{
+		StringValue: types.StringUnknown(),
+	}
+}
+
+func YAMLValue(value string) YAML {
+	return YAML{
+		StringValue: types.StringValue(value),
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYAMLStringSemanticEquals(t *testing.T) {
+	manifest := "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: flux-system\n  labels:\n    app: flux\n"
+	tests := []struct {
+		name     string
+		oldValue string
+		newValue string
+		expected bool
+	}{
+		{
+			name:     "identical",
+			oldValue: manifest,
+			newValue: manifest,
+			expected: true,
+		},
+		{
+			name:     "reordered keys and indentation",
+			oldValue: manifest,
+			newValue: "kind: Namespace\napiVersion: v1\nmetadata:\n    labels:\n        app: flux\n    name: flux-system",
+			expected: true,
+		},
+		{
+			name:     "comments and empty documents",
+			oldValue: "---\n" + manifest + "---\n",
+			newValue: "# generated\n" + manifest + "\n\n",
+			expected: true,
+		},
+		{
+			name:     "changed value",
+			oldValue: manifest,
+			newValue: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: flux\n  labels:\n    app: flux\n",
+			expected: false,
+		},
+		{
+			name:     "reordered documents",
+			oldValue: "kind: Namespace\n---\nkind: ServiceAccount\n",
+			newValue: "kind: ServiceAccount\n---\nkind: Namespace\n",
+			expected: false,
+		},
+		{
+			name:     "empty content",
+			oldValue: manifest,
+			newValue: "",
+			expected: false,
+		},
+		{
+			name:     "invalid yaml",
+			oldValue: "key: [value",
+			newValue: "key: [value ",
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			equal, diags := YAMLValue(tt.oldValue).StringSemanticEquals(context.Background(), YAMLValue(tt.newValue))
+			require.False(t, diags.HasError())
+			require.Equal(t, tt.expected, equal)
+		})
+	}
+}
+
+func TestYAMLTypeValueFromTerraform(t *testing.T) {
+	ctx := context.Background()
+	val, err := YAMLType{}.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, "kind: Namespace\n"))
+	require.NoError(t, err)
+	require.Equal(t, YAMLValue("kind: Namespace\n"), val)
+	require.Equal(t, YAMLType{}, val.Type(ctx))
+
+	val, err = YAMLType{}.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, tftypes.UnknownValue))
+	require.NoError(t, err)
+	require.Equal(t, YAMLUnknown(), val)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/fluxcd/flux2/v2/pkg/manifestgen/install"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	customtypes "github.com/fluxcd/terraform-provider-flux/internal/framework/types"
+	"github.com/fluxcd/terraform-provider-flux/internal/framework/validators"
+	"github.com/fluxcd/terraform-provider-flux/internal/utils"
+)
+
+type installManifestsDataSourceData struct {
+	installOptionsData
+	Content   types.String `tfsdk:"content"`
+	Documents types.List   `tfsdk:"documents"`
+	FilePath  types.String `tfsdk:"file_path"`
+	ID        types.String `tfsdk:"id"`
+	Path      types.String `tfsdk:"path"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &installManifestsDataSource{}
+
+type installManifestsDataSource struct{}
+
+func NewInstallManifestsDataSource() datasource.DataSource {
+	return &installManifestsDataSource{}
+}
+
+func (d *installManifestsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_install_manifests"
+}
+
+func (d *installManifestsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	defaultOpts := install.MakeDefaultOptions()
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Renders the Flux install manifests (gotk-components.yaml) without accessing a Git repository or a Kubernetes cluster.",
+		Attributes: map[string]schema.Attribute{
+			"cluster_domain": schema.StringAttribute{
+				Description: fmt.Sprintf("The internal cluster domain. Defaults to `%s`", defaultOpts.ClusterDomain),
+				Optional:    true,
+				Computed:    true,
+			},
+			"components": schema.SetAttribute{
+				ElementType: types.StringType,
+				Description: fmt.Sprintf("Toolkit components to include in the install manifests. Defaults to `%s`", defaultOpts.Components),
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(2),
+					setvalidator.ValueStringsAre(stringvalidator.OneOf("source-controller", "kustomize-controller", "helm-controller", "notification-controller")),
+					validators.MustContain("source-controller", "kustomize-controller"),
+				},
+			},
+			"components_extra": schema.SetAttribute{
+				ElementType: types.StringType,
+				Description: "List of extra components to include in the install manifests.",
+				Optional:    true,
+				Validators: []validator.Set{
+					setvalidator.SizeAtMost(3),
+					setvalidator.ValueStringsAre(stringvalidator.OneOf("image-reflector-controller", "image-automation-controller", "source-watcher")),
+				},
+			},
+			"content": schema.StringAttribute{
+				Description: "Rendered install manifests as a multi-document YAML string.",
+				Computed:    true,
+			},
+			"documents": schema.ListAttribute{
+				ElementType: types.StringType,
+				Description: "Rendered install manifests split into one YAML document per Kubernetes object.",
+				Computed:    true,
+			},
+			"embedded_manifests": schema.BoolAttribute{
+				Description: "When enabled, the Flux manifests will be extracted from the provider binary instead of being downloaded from GitHub.com. Defaults to `false`.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"file_path": schema.StringAttribute{
+				Description: "Path of the install manifests file relative to the repository root.",
+				Computed:    true,
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"image_pull_secret": schema.StringAttribute{
+				Description: "Kubernetes secret name used for pulling the toolkit images from a private registry.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(rfc1123DomainRegex), rfc1123DomainError),
+					stringvalidator.LengthAtMost(253),
+				},
+			},
+			"log_level": schema.StringAttribute{
+				Description: fmt.Sprintf("Log level for toolkit components. Defaults to `%s`.", defaultOpts.LogLevel),
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("info", "debug", "error"),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				Description: fmt.Sprintf("The namespace scope for install manifests. Defaults to `%s`.", defaultOpts.Namespace),
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(rfc1123LabelRegex), rfc1123LabelError),
+					stringvalidator.LengthAtMost(63),
+				},
+			},
+			"network_policy": schema.BoolAttribute{
+				Description: fmt.Sprintf("Deny ingress access to the toolkit controllers from other namespaces using network policies. Defaults to `%v`.", defaultOpts.NetworkPolicy),
+				Optional:    true,
+				Computed:    true,
+			},
+			"path": schema.StringAttribute{
+				Description: "Path relative to the repository root the install manifests file path is computed from.",
+				Optional:    true,
+			},
+			"registry": schema.StringAttribute{
+				CustomType:  customtypes.RegistryType{},
+				Description: fmt.Sprintf("Container registry where the toolkit images are published, without a scheme. Defaults to `%s`.", defaultOpts.Registry),
+				Optional:    true,
+				Computed:    true,
+			},
+			"registry_credentials": schema.StringAttribute{
+				Description: "Container registry credentials in the format 'user:password'",
+				Optional:    true,
+			},
+			"toleration_keys": schema.SetAttribute{
+				ElementType: types.StringType,
+				Description: "List of toleration keys used to schedule the components pods onto nodes with matching taints.",
+				Optional:    true,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(regexp.MustCompile(tolerationKeyRegex), tolerationKeyError),
+						stringvalidator.LengthAtMost(253),
+					),
+				},
+			},
+			"version": schema.StringAttribute{
+				Description: fmt.Sprintf("Flux version. Defaults to `%s`. Has no effect when `embedded_manifests` is enabled.", utils.DefaultFluxVersion),
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile("(latest|^v.*)"), "must either be latest or start with 'v'"),
+				},
+			},
+			"watch_all_namespaces": schema.BoolAttribute{
+				Description: fmt.Sprintf("If true watch for custom resources in all namespaces. Defaults to `%v`.", defaultOpts.WatchAllNamespaces),
+				Optional:    true,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Read generates the install manifests from the configured options.
+func (d *installManifestsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data installManifestsDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setInstallOptionsDefaults(ctx, &data.installOptionsData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	installOpts := getInstallOptions(data.installOptionsData, "", data.Path.ValueString())
+	manifest, err := install.Generate(installOpts, getManifestsBase(data.installOptionsData))
+	if err != nil {
+		resp.Diagnostics.AddError("Could not generate install manifests", err.Error())
+		return
+	}
+	docs, err := utils.SplitYAMLDocuments(manifest.Content)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not split install manifests", err.Error())
+		return
+	}
+	docsValue, diags := types.ListValueFrom(ctx, types.StringType, docs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Content = types.StringValue(manifest.Content)
+	data.Documents = docsValue
+	data.FilePath = types.StringValue(manifest.Path)
+	data.ID = types.StringValue(manifest.Path)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccInstallManifestsDataSource_Basic(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "flux" {}
+				data "flux_install_manifests" "this" {
+				  path            = "clusters/staging"
+				  toleration_keys = ["FooBar"]
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.flux_install_manifests.this", "namespace", "flux-system"),
+					resource.TestCheckResourceAttr("data.flux_install_manifests.this", "file_path", "clusters/staging/flux-system/gotk-components.yaml"),
+					resource.TestMatchResourceAttr("data.flux_install_manifests.this", "content", regexp.MustCompile("kind: CustomResourceDefinition")),
+					resource.TestMatchResourceAttr("data.flux_install_manifests.this", "documents.0", regexp.MustCompile("kind: Namespace")),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstallManifestsDataSource_InvalidComponents(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "flux" {}
+				data "flux_install_manifests" "this" {
+				  components = ["source-controller", "helm-controller"]
+				}
+				`,
+				ExpectError: regexp.MustCompile("Set has to contain the items"),
+			},
+		},
+	})
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/fluxcd/flux2/v2/pkg/manifestgen/install"
+	"github.com/fluxcd/flux2/v2/pkg/manifestgen/sync"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	customtypes "github.com/fluxcd/terraform-provider-flux/internal/framework/types"
+	"github.com/fluxcd/terraform-provider-flux/internal/framework/validators"
+	"github.com/fluxcd/terraform-provider-flux/internal/utils"
+)
+
+type syncManifestsDataSourceData struct {
+	Branch            types.String         `tfsdk:"branch"`
+	Content           types.String         `tfsdk:"content"`
+	Documents         types.List           `tfsdk:"documents"`
+	FilePath          types.String         `tfsdk:"file_path"`
+	ID                types.String         `tfsdk:"id"`
+	Interval          customtypes.Duration `tfsdk:"interval"`
+	Namespace         types.String         `tfsdk:"namespace"`
+	Path              types.String         `tfsdk:"path"`
+	RecurseSubmodules types.Bool           `tfsdk:"recurse_submodules"`
+	SecretName        types.String         `tfsdk:"secret_name"`
+	Url               customtypes.URL      `tfsdk:"url"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &syncManifestsDataSource{}
+
+type syncManifestsDataSource struct{}
+
+func NewSyncManifestsDataSource() datasource.DataSource {
+	return &syncManifestsDataSource{}
+}
+
+func (d *syncManifestsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sync_manifests"
+}
+
+func (d *syncManifestsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	defaultOpts := install.MakeDefaultOptions()
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Renders the Flux sync manifests (gotk-sync.yaml) containing the root GitRepository and Kustomization without accessing a Git repository or a Kubernetes cluster.",
+		Attributes: map[string]schema.Attribute{
+			"branch": schema.StringAttribute{
+				Description: fmt.Sprintf("Branch of the repository to reconcile from. Defaults to `%s`.", defaultBranch),
+				Optional:    true,
+				Computed:    true,
+			},
+			"content": schema.StringAttribute{
+				Description: "Rendered sync manifests as a multi-document YAML string.",
+				Computed:    true,
+			},
+			"documents": schema.ListAttribute{
+				ElementType: types.StringType,
+				Description: "Rendered sync manifests split into one YAML document per Kubernetes object.",
+				Computed:    true,
+			},
+			"file_path": schema.StringAttribute{
+				Description: "Path of the sync manifests file relative to the repository root.",
+				Computed:    true,
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"interval": schema.StringAttribute{
+				CustomType:  customtypes.DurationType{},
+				Description: fmt.Sprintf("Interval at which to reconcile from the repository. Defaults to `%s`.", time.Minute.String()),
+				Optional:    true,
+				Computed:    true,
+			},
+			"namespace": schema.StringAttribute{
+				Description: fmt.Sprintf("The namespace of the GitRepository and Kustomization. Defaults to `%s`.", defaultOpts.Namespace),
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(rfc1123LabelRegex), rfc1123LabelError),
+					stringvalidator.LengthAtMost(63),
+				},
+			},
+			"path": schema.StringAttribute{
+				Description: "Path relative to the repository root, when specified the cluster sync will be scoped to this path.",
+				Optional:    true,
+			},
+			"recurse_submodules": schema.BoolAttribute{
+				Description: "Configures the GitRepository source to initialize and include Git submodules in the artifact it produces.",
+				Optional:    true,
+			},
+			"secret_name": schema.StringAttribute{
+				Description: fmt.Sprintf("Name of the secret the sync credentials can be found in. Defaults to `%s`.", defaultOpts.Namespace),
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(rfc1123DomainRegex), rfc1123DomainError),
+					stringvalidator.LengthAtMost(253),
+				},
+			},
+			"url": schema.StringAttribute{
+				CustomType:  customtypes.URLType{},
+				Description: "Url of the Git repository to reconcile from.",
+				Required:    true,
+				Validators: []validator.String{
+					validators.URLScheme(httpScheme, "https", "ssh"),
+				},
+			},
+		},
+	}
+}
+
+// Read generates the sync manifests from the configured options.
+func (d *syncManifestsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data syncManifestsDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set default values.
+	if data.Branch.IsNull() {
+		data.Branch = types.StringValue(defaultBranch)
+	}
+	if data.Interval.IsNull() {
+		data.Interval = customtypes.DurationValue(time.Minute)
+	}
+	if data.Namespace.IsNull() {
+		data.Namespace = types.StringValue(install.MakeDefaultOptions().Namespace)
+	}
+	if data.SecretName.IsNull() {
+		data.SecretName = types.StringValue(install.MakeDefaultOptions().Namespace)
+	}
+
+	syncOpts := getSyncOptions(syncOptionsData{
+		Branch:            data.Branch.ValueString(),
+		Interval:          data.Interval.ValueDuration(),
+		Namespace:         data.Namespace.ValueString(),
+		Path:              data.Path.ValueString(),
+		RecurseSubmodules: data.RecurseSubmodules.ValueBool(),
+		SecretName:        data.SecretName.ValueString(),
+		URL:               data.Url.ValueURL(),
+	})
+	manifest, err := sync.Generate(syncOpts)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not generate sync manifests", err.Error())
+		return
+	}
+	docs, err := utils.SplitYAMLDocuments(manifest.Content)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not split sync manifests", err.Error())
+		return
+	}
+	docsValue, diags := types.ListValueFrom(ctx, types.StringType, docs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Content = types.StringValue(manifest.Content)
+	data.Documents = docsValue
+	data.FilePath = types.StringValue(manifest.Path)
+	data.ID = types.StringValue(manifest.Path)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
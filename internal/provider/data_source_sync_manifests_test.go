@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccSyncManifestsDataSource_Basic(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "flux" {}
+				data "flux_sync_manifests" "this" {
+				  url                = "ssh://git@git.example/fleet.git"
+				  path               = "clusters/staging"
+				  interval           = "5m"
+				  recurse_submodules = true
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.flux_sync_manifests.this", "branch", "main"),
+					resource.TestCheckResourceAttr("data.flux_sync_manifests.this", "file_path", "clusters/staging/flux-system/gotk-sync.yaml"),
+					resource.TestCheckResourceAttr("data.flux_sync_manifests.this", "documents.#", "2"),
+					resource.TestMatchResourceAttr("data.flux_sync_manifests.this", "documents.0", regexp.MustCompile("kind: GitRepository")),
+					resource.TestMatchResourceAttr("data.flux_sync_manifests.this", "documents.1", regexp.MustCompile("kind: Kustomization")),
+					resource.TestMatchResourceAttr("data.flux_sync_manifests.this", "content", regexp.MustCompile("recurseSubmodules: true")),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSyncManifestsDataSource_InvalidURL(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "flux" {}
+				data "flux_sync_manifests" "this" {
+				  url = "ftp://git.example/fleet.git"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Invalid URL scheme"),
+			},
+		},
+	})
+}
@@ -0,0 +1,635 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/flux2/v2/pkg/log"
+	"github.com/fluxcd/flux2/v2/pkg/manifestgen/install"
+	"github.com/fluxcd/flux2/v2/pkg/uninstall"
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	runclient "github.com/fluxcd/pkg/runtime/client"
+	"github.com/fluxcd/pkg/ssa"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	customtypes "github.com/fluxcd/terraform-provider-flux/internal/framework/types"
+	"github.com/fluxcd/terraform-provider-flux/internal/framework/validators"
+	"github.com/fluxcd/terraform-provider-flux/internal/utils"
+)
+
+// versionLabel is set to the Flux version on every object of the install manifests.
+const versionLabel = "app.kubernetes.io/version"
+
+// Deletion policies deciding what happens to Flux in the cluster when a resource is destroyed.
+const (
+	deletionPolicyUninstall = "uninstall"
+	deletionPolicyOrphan    = "orphan"
+	deletionPolicySuspend   = "suspend"
+	deletionPolicyKeepCRDs  = "keep_crds"
+)
+
+var deletionPolicies = []string{deletionPolicyUninstall, deletionPolicyOrphan, deletionPolicySuspend, deletionPolicyKeepCRDs}
+
+// installOptionsData holds the attributes used to render the Flux install manifests.
+// It is embedded by every resource and data source that generates gotk-components.yaml.
+type installOptionsData struct {
+	ClusterDomain       types.String         `tfsdk:"cluster_domain"`
+	Components          types.Set            `tfsdk:"components"`
+	ComponentsExtra     types.Set            `tfsdk:"components_extra"`
+	EmbeddedManifests   types.Bool           `tfsdk:"embedded_manifests"`
+	ImagePullSecret     types.String         `tfsdk:"image_pull_secret"`
+	LogLevel            types.String         `tfsdk:"log_level"`
+	Namespace           types.String         `tfsdk:"namespace"`
+	NetworkPolicy       types.Bool           `tfsdk:"network_policy"`
+	Registry            customtypes.Registry `tfsdk:"registry"`
+	RegistryCredentials types.String         `tfsdk:"registry_credentials"`
+	TolerationKeys      types.Set            `tfsdk:"toleration_keys"`
+	Version             types.String         `tfsdk:"version"`
+	WatchAllNamespaces  types.Bool           `tfsdk:"watch_all_namespaces"`
+}
+
+// installSchemaAttributes returns the resource schema attributes matching installOptionsData.
+func installSchemaAttributes(ctx context.Context) (map[string]schema.Attribute, diag.Diagnostics) {
+	defaultOpts := install.MakeDefaultOptions()
+	componentsSet, diags := types.SetValueFrom(ctx, types.StringType, defaultOpts.Components)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	return map[string]schema.Attribute{
+		"cluster_domain": schema.StringAttribute{
+			Description: fmt.Sprintf("The internal cluster domain. Defaults to `%s`", defaultOpts.ClusterDomain),
+			Optional:    true,
+			Computed:    true,
+			Default:     stringdefault.StaticString(defaultOpts.ClusterDomain),
+		},
+		"components": schema.SetAttribute{
+			ElementType: types.StringType,
+			Description: fmt.Sprintf("Toolkit components to include in the install manifests. Defaults to `%s`", defaultOpts.Components),
+			Optional:    true,
+			Computed:    true,
+			Default:     setdefault.StaticValue(componentsSet),
+			Validators: []validator.Set{
+				setvalidator.SizeAtLeast(2),
+				setvalidator.ValueStringsAre(stringvalidator.OneOf("source-controller", "kustomize-controller", "helm-controller", "notification-controller")),
+				validators.MustContain("source-controller", "kustomize-controller"),
+			},
+		},
+		"components_extra": schema.SetAttribute{
+			ElementType: types.StringType,
+			Description: "List of extra components to include in the install manifests.",
+			Optional:    true,
+			Validators: []validator.Set{
+				setvalidator.SizeAtMost(3),
+				setvalidator.ValueStringsAre(stringvalidator.OneOf("image-reflector-controller", "image-automation-controller", "source-watcher")),
+			},
+		},
+		"embedded_manifests": schema.BoolAttribute{
+			Description: "When enabled, the Flux manifests will be extracted from the provider binary instead of being downloaded from GitHub.com. Defaults to `false`.",
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+		},
+		"image_pull_secret": schema.StringAttribute{
+			Description: "Kubernetes secret name used for pulling the toolkit images from a private registry.",
+			Optional:    true,
+			Validators: []validator.String{
+				stringvalidator.RegexMatches(regexp.MustCompile(rfc1123DomainRegex), rfc1123DomainError),
+				stringvalidator.LengthAtMost(253),
+			},
+		},
+		"log_level": schema.StringAttribute{
+			Description: fmt.Sprintf("Log level for toolkit components. Defaults to `%s`.", defaultOpts.LogLevel),
+			Optional:    true,
+			Computed:    true,
+			Default:     stringdefault.StaticString(defaultOpts.LogLevel),
+			Validators: []validator.String{
+				stringvalidator.OneOf("info", "debug", "error"),
+			},
+		},
+		"namespace": schema.StringAttribute{
+			Description: fmt.Sprintf("The namespace scope for install manifests. Defaults to `%s`. It will be created if it does not exist.", defaultOpts.Namespace),
+			Optional:    true,
+			Computed:    true,
+			Default:     stringdefault.StaticString(defaultOpts.Namespace),
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Validators: []validator.String{
+				stringvalidator.RegexMatches(regexp.MustCompile(rfc1123LabelRegex), rfc1123LabelError),
+				stringvalidator.LengthAtMost(63),
+			},
+		},
+		"network_policy": schema.BoolAttribute{
+			Description: fmt.Sprintf("Deny ingress access to the toolkit controllers from other namespaces using network policies. Defaults to `%v`.", defaultOpts.NetworkPolicy),
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(defaultOpts.NetworkPolicy),
+		},
+		"registry": schema.StringAttribute{
+			CustomType:  customtypes.RegistryType{},
+			Description: fmt.Sprintf("Container registry where the toolkit images are published, without a scheme. Defaults to `%s`.", defaultOpts.Registry),
+			Optional:    true,
+			Computed:    true,
+			Default:     stringdefault.StaticString(defaultOpts.Registry),
+		},
+		"registry_credentials": schema.StringAttribute{
+			Description: "Container registry credentials in the format 'user:password'",
+			Optional:    true,
+		},
+		"toleration_keys": schema.SetAttribute{
+			ElementType: types.StringType,
+			Description: "List of toleration keys used to schedule the components pods onto nodes with matching taints.",
+			Optional:    true,
+			Validators: []validator.Set{
+				setvalidator.ValueStringsAre(
+					stringvalidator.RegexMatches(regexp.MustCompile(tolerationKeyRegex), tolerationKeyError),
+					stringvalidator.LengthAtMost(253),
+				),
+			},
+		},
+		"version": schema.StringAttribute{
+			Description: fmt.Sprintf("Flux version. Defaults to `%s`. Has no effect when `embedded_manifests` is enabled.", utils.DefaultFluxVersion),
+			Optional:    true,
+			Computed:    true,
+			Default:     stringdefault.StaticString(utils.DefaultFluxVersion),
+			Validators: []validator.String{
+				stringvalidator.RegexMatches(regexp.MustCompile("(latest|^v.*)"), "must either be latest or start with 'v'"),
+			},
+		},
+		"watch_all_namespaces": schema.BoolAttribute{
+			Description: fmt.Sprintf("If true watch for custom resources in all namespaces. Defaults to `%v`.", defaultOpts.WatchAllNamespaces),
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(defaultOpts.WatchAllNamespaces),
+		},
+	}, nil
+}
+
+// validateInstallOptions validates the combination of install attributes.
+func validateInstallOptions(data installOptionsData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if data.RegistryCredentials.ValueString() != "" && data.ImagePullSecret.ValueString() == "" {
+		diags.AddAttributeError(
+			path.Root("registry_credentials"),
+			"Missing image_pull_secret configuration",
+			"The image_pull_secret attribute must be configured when registry_credential is set.",
+		)
+	}
+	if data.RegistryCredentials.ValueString() != "" && len(strings.Split(data.RegistryCredentials.ValueString(), ":")) != 2 {
+		diags.AddAttributeError(
+			path.Root("registry_credentials"),
+			"Invalid registry_credential format",
+			"Expected 'user:password' format.",
+		)
+	}
+	return diags
+}
+
+// setInstallOptionsDefaults sets the install defaults for attributes that are not configured.
+// Data sources cannot declare defaults in their schema so they are applied when reading, the
+// resources set the same defaults in installSchemaAttributes.
+func setInstallOptionsDefaults(ctx context.Context, data *installOptionsData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	defaultOpts := install.MakeDefaultOptions()
+	if data.ClusterDomain.IsNull() {
+		data.ClusterDomain = types.StringValue(defaultOpts.ClusterDomain)
+	}
+	if data.Components.IsNull() {
+		components, d := types.SetValueFrom(ctx, types.StringType, defaultOpts.Components)
+		diags.Append(d...)
+		data.Components = components
+	}
+	if data.EmbeddedManifests.IsNull() {
+		data.EmbeddedManifests = types.BoolValue(false)
+	}
+	if data.LogLevel.IsNull() {
+		data.LogLevel = types.StringValue(defaultOpts.LogLevel)
+	}
+	if data.Namespace.IsNull() {
+		data.Namespace = types.StringValue(defaultOpts.Namespace)
+	}
+	if data.NetworkPolicy.IsNull() {
+		data.NetworkPolicy = types.BoolValue(defaultOpts.NetworkPolicy)
+	}
+	if data.Registry.IsNull() {
+		data.Registry = customtypes.RegistryValue(defaultOpts.Registry)
+	}
+	if data.Version.IsNull() {
+		data.Version = types.StringValue(utils.DefaultFluxVersion)
+	}
+	if data.WatchAllNamespaces.IsNull() {
+		data.WatchAllNamespaces = types.BoolValue(defaultOpts.WatchAllNamespaces)
+	}
+	return diags
+}
+
+func getInstallOptions(data installOptionsData, baseURL, targetPath string) install.Options {
+	components := []string{} //nolint:prealloc // ElementsAs replaces the slice, so preallocation is ineffective
+	data.Components.ElementsAs(context.Background(), &components, false)
+	sort.Strings(components)
+	componentsExtra := []string{}
+	data.ComponentsExtra.ElementsAs(context.Background(), &componentsExtra, false)
+	sort.Strings(componentsExtra)
+	components = append(components, componentsExtra...)
+
+	tolerationKeys := []string{}
+	data.TolerationKeys.ElementsAs(context.Background(), &tolerationKeys, false)
+	sort.Strings(tolerationKeys)
+
+	if baseURL == "" {
+		baseURL = install.MakeDefaultOptions().BaseURL
+	}
+
+	installOptions := install.Options{
+		BaseURL:                baseURL,
+		ClusterDomain:          data.ClusterDomain.ValueString(),
+		Components:             components,
+		ImagePullSecret:        data.ImagePullSecret.ValueString(),
+		LogLevel:               data.LogLevel.ValueString(),
+		ManifestFile:           install.MakeDefaultOptions().ManifestFile,
+		Namespace:              data.Namespace.ValueString(),
+		NetworkPolicy:          data.NetworkPolicy.ValueBool(),
+		NotificationController: install.MakeDefaultOptions().NotificationController,
+		Registry:               data.Registry.ValueString(),
+		RegistryCredential:     data.RegistryCredentials.ValueString(),
+		TargetPath:             targetPath,
+		Timeout:                install.MakeDefaultOptions().Timeout,
+		TolerationKeys:         tolerationKeys,
+		Version:                data.Version.ValueString(),
+		WatchAllNamespaces:     data.WatchAllNamespaces.ValueBool(),
+	}
+	return installOptions
+}
+
+// getManifestsBase returns the directory containing the embedded Flux manifests
+// or an empty string when the manifests should be downloaded.
+func getManifestsBase(data installOptionsData) string {
+	if data.EmbeddedManifests.ValueBool() {
+		return EmbeddedManifests
+	}
+	return ""
+}
+
+// getInstallObjects renders the install manifests and returns the Kubernetes objects
+// to apply, including the image pull secret when registry credentials are configured.
+func getInstallObjects(data installOptionsData) ([]*unstructured.Unstructured, error) {
+	installOpts := getInstallOptions(data, "", "")
+	manifest, err := install.Generate(installOpts, getManifestsBase(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not generate install manifests: %w", err)
+	}
+	objects, err := utils.ReadObjects(manifest.Content)
+	if err != nil {
+		return nil, fmt.Errorf("could not read install manifests: %w", err)
+	}
+	if data.RegistryCredentials.ValueString() == "" {
+		return objects, nil
+	}
+	secret, err := getImagePullSecret(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(objects, secret), nil
+}
+
+// getImagePullSecret generates the docker config Secret used to pull the toolkit images.
+func getImagePullSecret(data installOptionsData) (*unstructured.Unstructured, error) {
+	username, password, ok := strings.Cut(data.RegistryCredentials.ValueString(), ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid registry credentials format, expected 'user:password'")
+	}
+	registry, err := getRegistryHost(data.Registry.ValueString())
+	if err != nil {
+		return nil, err
+	}
+	return getDockerConfigSecret(data.ImagePullSecret.ValueString(), data.Namespace.ValueString(), registry, username, password)
+}
+
+// getRegistryHost returns the host of the registry the toolkit images are published to, which is the
+// key of the credentials in the docker config. The scheme and repository path are removed.
+func getRegistryHost(registry string) (string, error) {
+	host := registry
+	if _, rest, ok := strings.Cut(host, "://"); ok {
+		host = rest
+	}
+	host, _, _ = strings.Cut(host, "/")
+	if host == "" {
+		return "", fmt.Errorf("missing host in registry %s", registry)
+	}
+	if _, err := name.NewRegistry(host); err != nil {
+		return "", fmt.Errorf("invalid host in registry %s: %w", registry, err)
+	}
+	return host, nil
+}
+
+// getDockerConfigSecret generates a docker config Secret holding the credentials for the given registry host.
+func getDockerConfigSecret(name, namespace, registry, username, password string) (*unstructured.Unstructured, error) {
+	dockerConfig, err := json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			registry: map[string]string{
+				"username": username,
+				"password": password,
+				"auth":     base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", username, password))),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not generate docker config: %w", err)
+	}
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: dockerConfig,
+		},
+	}
+	return toUnstructured(secret)
+}
+
+// toUnstructured converts a typed Kubernetes object, which must have its TypeMeta set, to unstructured.
+// The status and creation timestamp are removed as they are never part of the desired state.
+func toUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("could not convert %T: %w", obj, err)
+	}
+	unstructured.RemoveNestedField(content, "status")
+	unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// getInventory returns the object identifiers mapped to the checksum of their desired state.
+func getInventory(objects []*unstructured.Unstructured) (map[string]string, error) {
+	inventory := map[string]string{}
+	for _, obj := range objects {
+		b, err := obj.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("could not marshal %s: %w", utils.ObjectKey(obj), err)
+		}
+		inventory[utils.ObjectKey(obj)] = fmt.Sprintf("sha256:%x", sha256.Sum256(b))
+	}
+	return inventory, nil
+}
+
+// applyObjects applies the objects with server-side apply, deletes the previous objects
+// no longer present and waits for the applied objects to become ready.
+func applyObjects(ctx context.Context, rcg *utils.RESTClientGetter, opts *runclient.Options, objects, previousObjects []*unstructured.Unstructured, timeout time.Duration) error {
+	resourceManager, err := utils.ResourceManager(rcg, opts)
+	if err != nil {
+		return fmt.Errorf("could not create resource manager: %w", err)
+	}
+	changeSet, err := resourceManager.ApplyAllStaged(ctx, objects, ssa.DefaultApplyOptions())
+	if err != nil {
+		return fmt.Errorf("could not apply manifests: %w", err)
+	}
+
+	desired := map[string]bool{}
+	for _, obj := range objects {
+		desired[utils.ObjectKey(obj)] = true
+	}
+	stale := []*unstructured.Unstructured{}
+	for _, obj := range previousObjects {
+		if !desired[utils.ObjectKey(obj)] {
+			stale = append(stale, obj)
+		}
+	}
+	if len(stale) > 0 {
+		_, err := resourceManager.DeleteAll(ctx, stale, ssa.DeleteOptions{PropagationPolicy: metav1.DeletePropagationBackground})
+		if err != nil {
+			return fmt.Errorf("could not remove stale objects: %w", err)
+		}
+	}
+
+	err = resourceManager.WaitForSet(changeSet.ToObjMetadataSet(), ssa.WaitOptions{
+		Interval: 2 * time.Second,
+		Timeout:  timeout,
+	})
+	if err != nil {
+		return fmt.Errorf("timeout waiting for objects to become ready: %w", err)
+	}
+	return nil
+}
+
+// detectDrift compares the objects with their state in the cluster. The checksum of drifted objects
+// is reset in the inventory and a description of the drift is returned for each of them.
+func detectDrift(ctx context.Context, rcg *utils.RESTClientGetter, opts *runclient.Options, objects []*unstructured.Unstructured, inventory map[string]string) ([]string, error) {
+	resourceManager, err := utils.ResourceManager(rcg, opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not create resource manager: %w", err)
+	}
+	drifted := []string{}
+	for _, obj := range objects {
+		entry, _, _, err := resourceManager.Diff(ctx, obj, ssa.DiffOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("could not detect drift for %s: %w", utils.ObjectKey(obj), err)
+		}
+		if entry.Action == ssa.UnchangedAction {
+			continue
+		}
+		inventory[utils.ObjectKey(obj)] = ""
+		drifted = append(drifted, fmt.Sprintf("%s %s", utils.ObjectKey(obj), entry.Action))
+	}
+	sort.Strings(drifted)
+	return drifted, nil
+}
+
+// detectComponentDrift compares the controller Deployments, CRDs and NetworkPolicies in the cluster with
+// the rendered install objects. Deployments are checked for replicas, images and args and all objects
+// for the Flux version label, unless compareSpec is false in which case only their presence is checked.
+// A description is returned for each drifted object.
+func detectComponentDrift(ctx context.Context, kubeClient client.Client, objects []*unstructured.Unstructured, compareSpec bool) ([]string, error) {
+	drifted := []string{}
+	for _, obj := range objects {
+		var live client.Object
+		switch obj.GetKind() {
+		case "Deployment":
+			live = &appsv1.Deployment{}
+		case "CustomResourceDefinition":
+			live = &apiextensionsv1.CustomResourceDefinition{}
+		case "NetworkPolicy":
+			live = &networkingv1.NetworkPolicy{}
+		default:
+			continue
+		}
+		err := kubeClient.Get(ctx, client.ObjectKeyFromObject(obj), live)
+		if k8serrors.IsNotFound(err) {
+			drifted = append(drifted, fmt.Sprintf("%s is missing", utils.ObjectKey(obj)))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not get %s: %w", utils.ObjectKey(obj), err)
+		}
+		if !compareSpec {
+			continue
+		}
+
+		if expected, actual := obj.GetLabels()[versionLabel], live.GetLabels()[versionLabel]; expected != actual {
+			drifted = append(drifted, fmt.Sprintf("%s has version %q, expected %q", utils.ObjectKey(obj), actual, expected))
+		}
+		deployment, ok := live.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		expected := appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &expected); err != nil {
+			return nil, fmt.Errorf("could not convert %s: %w", utils.ObjectKey(obj), err)
+		}
+		drifted = append(drifted, diffDeployment(utils.ObjectKey(obj), expected, *deployment)...)
+	}
+	sort.Strings(drifted)
+	return drifted, nil
+}
+
+// diffDeployment describes the differences in replicas, images and args between the Deployments.
+func diffDeployment(key string, expected, actual appsv1.Deployment) []string {
+	drifted := []string{}
+	expectedReplicas, actualReplicas := int32(1), int32(1)
+	if expected.Spec.Replicas != nil {
+		expectedReplicas = *expected.Spec.Replicas
+	}
+	if actual.Spec.Replicas != nil {
+		actualReplicas = *actual.Spec.Replicas
+	}
+	if expectedReplicas != actualReplicas {
+		drifted = append(drifted, fmt.Sprintf("%s has %d replicas, expected %d", key, actualReplicas, expectedReplicas))
+	}
+	for _, container := range expected.Spec.Template.Spec.Containers {
+		liveContainer, err := utils.GetContainer(actual.Spec.Template.Spec.Containers, container.Name)
+		if err != nil {
+			drifted = append(drifted, fmt.Sprintf("%s is missing container %s", key, container.Name))
+			continue
+		}
+		if liveContainer.Image != container.Image {
+			drifted = append(drifted, fmt.Sprintf("%s container %s has image %q, expected %q", key, container.Name, liveContainer.Image, container.Image))
+		}
+		if !slices.Equal(liveContainer.Args, container.Args) {
+			drifted = append(drifted, fmt.Sprintf("%s container %s has args %v, expected %v", key, container.Name, liveContainer.Args, container.Args))
+		}
+	}
+	return drifted
+}
+
+// deleteFlux removes Flux from the cluster according to the deletion policy. The root source is the
+// empty object of the kind Flux syncs the cluster from, it is suspended together with the root Kustomization.
+func deleteFlux(ctx context.Context, kubeClient client.Client, namespace, policy string, keepNamespace bool, rootSource client.Object) diag.Diagnostics {
+	var diags diag.Diagnostics
+	switch policy {
+	case deletionPolicyOrphan:
+		tflog.Debug(ctx, "The deletion policy is orphan. Skipping removal of Flux.", map[string]interface{}{})
+	case deletionPolicySuspend:
+		diags.Append(suspendRootSync(ctx, kubeClient, namespace, rootSource)...)
+	case deletionPolicyKeepCRDs:
+		// The finalizers are removed as the controllers handling them are removed,
+		// the namespace is kept as it contains the root sync objects.
+		if err := uninstall.Components(ctx, log.NopLogger{}, kubeClient, namespace, false); err != nil {
+			diags.AddError("Unable to remove Flux components", err.Error())
+		}
+		if err := uninstall.Finalizers(ctx, log.NopLogger{}, kubeClient, false); err != nil {
+			diags.AddError("Unable to remove finalizers", err.Error())
+		}
+	default:
+		diags.Append(uninstallFlux(ctx, kubeClient, namespace, keepNamespace)...)
+	}
+	return diags
+}
+
+// suspendRootSync suspends the root Kustomization and the root source, which stops Flux from applying
+// and pruning the objects in the cluster.
+func suspendRootSync(ctx context.Context, kubeClient client.Client, namespace string, rootSource client.Object) diag.Diagnostics {
+	var diags diag.Diagnostics
+	rootSource.SetName(namespace)
+	rootSource.SetNamespace(namespace)
+	rootSync := &kustomizev1.Kustomization{}
+	rootSync.SetName(namespace)
+	rootSync.SetNamespace(namespace)
+	patch := client.RawPatch(apitypes.MergePatchType, []byte(`{"spec":{"suspend":true}}`))
+	for _, obj := range []client.Object{rootSync, rootSource} {
+		if err := kubeClient.Patch(ctx, obj, patch); err != nil && !k8serrors.IsNotFound(err) {
+			diags.AddError(fmt.Sprintf("Unable to suspend %s/%s", obj.GetNamespace(), obj.GetName()), err.Error())
+		}
+	}
+	return diags
+}
+
+// uninstallFlux removes the Flux components, finalizers, CRDs and optionally the namespace from the cluster.
+func uninstallFlux(ctx context.Context, kubeClient client.Client, namespace string, keepNamespace bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if err := uninstall.Components(ctx, log.NopLogger{}, kubeClient, namespace, false); err != nil {
+		diags.AddError("Unable to remove Flux components", err.Error())
+		tflog.Debug(ctx, "Unable to remove Flux components", map[string]interface{}{})
+	}
+	if err := uninstall.Finalizers(ctx, log.NopLogger{}, kubeClient, false); err != nil {
+		diags.AddError("Unable to remove finalizers", err.Error())
+		tflog.Debug(ctx, "Unable to remove finalizers", map[string]interface{}{})
+	}
+	if err := uninstall.CustomResourceDefinitions(ctx, log.NopLogger{}, kubeClient, false); err != nil {
+		diags.AddError("Unable to remove CRDs", err.Error())
+		tflog.Debug(ctx, "Unable to remove CRDs", map[string]interface{}{})
+	}
+
+	// Only remove namespace if not keeping it.
+	if keepNamespace {
+		tflog.Debug(ctx, fmt.Sprintf("The keep_namespace variable was set to true. Skipping removal of %s namespace.", namespace), map[string]interface{}{})
+		return diags
+	}
+	if err := uninstall.Namespace(ctx, log.NopLogger{}, kubeClient, namespace, false); err != nil {
+		diags.AddError(fmt.Sprintf("Unable to remove %s namespace.", namespace), err.Error())
+		tflog.Debug(ctx, fmt.Sprintf("Unable to remove %s namespace.", namespace), map[string]interface{}{})
+	}
+	return diags
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	customtypes "github.com/fluxcd/terraform-provider-flux/internal/framework/types"
+)
+
+func TestGetImagePullSecret(t *testing.T) {
+	tests := []struct {
+		name     string
+		registry string
+		expected string
+	}{
+		{
+			name:     "host and path",
+			registry: "ghcr.io/fluxcd",
+			expected: "ghcr.io",
+		},
+		{
+			name:     "scheme, port and path",
+			registry: "https://registry.example.com:5000/fluxcd/flux2",
+			expected: "registry.example.com:5000",
+		},
+		{
+			name:     "host only",
+			registry: "registry.example.com",
+			expected: "registry.example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := installOptionsData{
+				ImagePullSecret:     types.StringValue("flux-pull"),
+				Namespace:           types.StringValue("flux-system"),
+				Registry:            customtypes.RegistryValue(tt.registry),
+				RegistryCredentials: types.StringValue("user:password"),
+			}
+			secret, err := getImagePullSecret(data)
+			require.NoError(t, err)
+			encoded, ok, err := unstructured.NestedString(secret.Object, "data", corev1.DockerConfigJsonKey)
+			require.NoError(t, err)
+			require.True(t, ok)
+			dockerConfig, err := base64.StdEncoding.DecodeString(encoded)
+			require.NoError(t, err)
+			auths := struct {
+				Auths map[string]interface{} `json:"auths"`
+			}{}
+			require.NoError(t, json.Unmarshal(dockerConfig, &auths))
+			require.Contains(t, auths.Auths, tt.expected)
+			require.Len(t, auths.Auths, 1)
+		})
+	}
+
+	_, err := getRegistryHost("https:///fluxcd")
+	require.Error(t, err)
+}
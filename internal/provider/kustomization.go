@@ -0,0 +1,232 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// validateKustomizationBuild runs a kustomize build of the kustomization.yaml in the directory of the
+// repository files, which reports broken patches and images before the files are pushed. Patches with
+// a target matching no object are reported as warnings, as kustomize ignores them.
+//
+// The build is skipped when the kustomization refers to files not managed by the provider, as those
+// are only available in the repository, or to remote resources, which are not fetched during plan.
+func validateKustomizationBuild(ctx context.Context, attrPath tfpath.Path, repositoryFiles map[string]string, dir string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	files := map[string]string{}
+	for p, content := range repositoryFiles {
+		files[filepath.ToSlash(p)] = content
+	}
+
+	kustomizationPath := path.Join(dir, konfig.DefaultKustomizationFileName())
+	kus := kustypes.Kustomization{}
+	if err := yaml.Unmarshal([]byte(files[kustomizationPath]), &kus); err != nil {
+		diags.AddAttributeError(attrPath, "Could not parse kustomization", err.Error())
+		return diags
+	}
+	kus.FixKustomization()
+	for _, image := range kus.Images {
+		if err := validateImage(image); err != nil {
+			diags.AddAttributeError(attrPath, "Invalid image", fmt.Sprintf("Image %s: %s", image.Name, err))
+		}
+	}
+	if diags.HasError() {
+		return diags
+	}
+	for _, f := range referencedFiles(kus) {
+		if _, ok := files[path.Join(dir, f)]; !ok {
+			tflog.Debug(ctx, "Skip building kustomization referring to files not managed by the provider", map[string]interface{}{"path": f})
+			return diags
+		}
+	}
+
+	fs := filesys.MakeFsInMemory()
+	for p, content := range files {
+		if err := fs.WriteFile(path.Join("/", p), []byte(content)); err != nil {
+			diags.AddAttributeError(attrPath, "Could not build kustomization", err.Error())
+			return diags
+		}
+	}
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fs, path.Join("/", dir))
+	if err != nil {
+		diags.AddAttributeError(attrPath, "Could not build kustomization", err.Error())
+		return diags
+	}
+
+	for _, patch := range append(kus.Patches, kus.PatchesJson6902...) {
+		if patch.Target == nil {
+			continue
+		}
+		matches, err := resMap.Select(*patch.Target)
+		if err != nil {
+			diags.AddAttributeError(attrPath, "Invalid patch target", err.Error())
+			continue
+		}
+		if len(matches) == 0 {
+			diags.AddAttributeWarning(
+				attrPath,
+				"Patch target matches no object",
+				fmt.Sprintf("The patch with target %s is not applied to any object.", describeSelector(*patch.Target)),
+			)
+		}
+	}
+	return diags
+}
+
+// prependPatch adds the patch in front of the patches of the kustomization. The rest of the
+// kustomization is kept as is, including its comments.
+func prependPatch(kustomization string, patch kustypes.Patch) (string, error) {
+	node, err := kyaml.Parse(kustomization)
+	if err != nil {
+		return "", fmt.Errorf("could not parse kustomization: %w", err)
+	}
+	patches, err := node.Pipe(kyaml.LookupCreate(kyaml.SequenceNode, "patches"))
+	if err != nil {
+		return "", fmt.Errorf("could not get patches of kustomization: %w", err)
+	}
+	b, err := yaml.Marshal(patch)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal patch: %w", err)
+	}
+	patchNode, err := kyaml.Parse(string(b))
+	if err != nil {
+		return "", fmt.Errorf("could not parse patch: %w", err)
+	}
+	patches.YNode().Content = append([]*kyaml.Node{patchNode.YNode()}, patches.YNode().Content...)
+	return node.String()
+}
+
+// removeFirstPatch removes the first patch of the kustomization, the rest of the kustomization is
+// kept as is. It reports false when the kustomization has no patches.
+func removeFirstPatch(kustomization string) (string, bool, error) {
+	node, err := kyaml.Parse(kustomization)
+	if err != nil {
+		return "", false, fmt.Errorf("could not parse kustomization: %w", err)
+	}
+	patches, err := node.Pipe(kyaml.Lookup("patches"))
+	if err != nil {
+		return "", false, fmt.Errorf("could not get patches of kustomization: %w", err)
+	}
+	if patches == nil || patches.YNode().Kind != kyaml.SequenceNode || len(patches.YNode().Content) == 0 {
+		return kustomization, false, nil
+	}
+	patches.YNode().Content = patches.YNode().Content[1:]
+	if len(patches.YNode().Content) == 0 {
+		if err := node.PipeE(kyaml.Clear("patches")); err != nil {
+			return "", false, fmt.Errorf("could not remove patches of kustomization: %w", err)
+		}
+	}
+	result, err := node.String()
+	return result, true, err
+}
+
+// referencedFiles returns the local files and directories the fixed kustomization refers to. Remote
+// resources are returned as well, as they can not be found in the repository files.
+func referencedFiles(kus kustypes.Kustomization) []string {
+	files := []string{}
+	files = append(files, kus.Resources...)
+	files = append(files, kus.Components...)
+	files = append(files, kus.Crds...)
+	files = append(files, kus.Configurations...)
+	files = append(files, kus.Generators...)
+	files = append(files, kus.Transformers...)
+	files = append(files, kus.Validators...)
+	for _, patch := range kus.Patches {
+		if patch.Path != "" {
+			files = append(files, patch.Path)
+		}
+	}
+	for _, patch := range kus.PatchesStrategicMerge {
+		// Inline patches contain at least one line break, otherwise they refer to a file.
+		if !strings.Contains(string(patch), "\n") {
+			files = append(files, string(patch))
+		}
+	}
+	for _, patch := range kus.PatchesJson6902 {
+		if patch.Path != "" {
+			files = append(files, patch.Path)
+		}
+	}
+	generators := []kustypes.GeneratorArgs{}
+	for _, g := range kus.ConfigMapGenerator {
+		generators = append(generators, g.GeneratorArgs)
+	}
+	for _, g := range kus.SecretGenerator {
+		generators = append(generators, g.GeneratorArgs)
+	}
+	for _, g := range generators {
+		for _, source := range g.FileSources {
+			// File sources can be prefixed with the key in the format key=path.
+			if _, p, ok := strings.Cut(source, "="); ok {
+				source = p
+			}
+			files = append(files, source)
+		}
+		files = append(files, g.EnvSources...)
+	}
+	return files
+}
+
+// validateImage checks that the image override results in a valid image reference.
+func validateImage(image kustypes.Image) error {
+	ref := image.Name
+	if image.NewName != "" {
+		ref = image.NewName
+	}
+	if image.NewTag != "" {
+		ref = ref + ":" + image.NewTag
+	}
+	if image.Digest != "" {
+		ref = ref + "@" + image.Digest
+	}
+	_, err := name.ParseReference(ref)
+	return err
+}
+
+func describeSelector(s kustypes.Selector) string {
+	fields := []string{}
+	for _, f := range []struct{ key, value string }{
+		{"group", s.Group},
+		{"version", s.Version},
+		{"kind", s.Kind},
+		{"namespace", s.Namespace},
+		{"name", s.Name},
+		{"labelSelector", s.LabelSelector},
+		{"annotationSelector", s.AnnotationSelector},
+	} {
+		if f.value != "" {
+			fields = append(fields, fmt.Sprintf("%s=%s", f.key, f.value))
+		}
+	}
+	return strings.Join(fields, ",")
+}
@@ -21,10 +21,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 
 	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/ssh/knownhosts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -32,14 +39,23 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/fluxcd/terraform-provider-flux/internal/forge"
 	customtypes "github.com/fluxcd/terraform-provider-flux/internal/framework/types"
 	"github.com/fluxcd/terraform-provider-flux/internal/framework/validators"
+	"github.com/fluxcd/terraform-provider-flux/internal/sshsig"
 )
 
 const (
 	defaultBranch = "main"
 	defaultAuthor = "Flux"
 	httpScheme    = "http"
+
+	defaultPullRequestBranchPrefix = "flux-bootstrap-"
+
+	signingFormatGPG = "gpg"
+	signingFormatSSH = "ssh"
+
+	gitSSHKnownHostsEnvVar = "GIT_SSH_KNOWN_HOSTS"
 )
 
 var EmbeddedManifests string
@@ -49,26 +65,96 @@ type Ssh struct {
 	Password     types.String `tfsdk:"password"`
 	PrivateKey   types.String `tfsdk:"private_key"`
 	HostKeyAlgos types.List   `tfsdk:"hostkey_algos"`
+	KnownHosts   types.String `tfsdk:"known_hosts"`
 }
 
 type Http struct {
 	Username             types.String `tfsdk:"username"`
 	Password             types.String `tfsdk:"password"`
+	BearerToken          types.String `tfsdk:"bearer_token"`
 	InsecureHttpAllowed  types.Bool   `tfsdk:"allow_insecure_http"`
 	CertificateAuthority types.String `tfsdk:"certificate_authority"`
 }
 
+type Signing struct {
+	Format     types.String `tfsdk:"format"`
+	PrivateKey types.String `tfsdk:"private_key"`
+	Passphrase types.String `tfsdk:"passphrase"`
+}
+
+type Proxy struct {
+	Url        customtypes.URL `tfsdk:"url"`
+	Username   types.String    `tfsdk:"username"`
+	Password   types.String    `tfsdk:"password"`
+	NoProxy    types.List      `tfsdk:"no_proxy"`
+	SecretName types.String    `tfsdk:"secret_name"`
+}
+
+type GitHubApp struct {
+	AppID          types.String `tfsdk:"app_id"`
+	InstallationID types.String `tfsdk:"installation_id"`
+	PrivateKey     types.String `tfsdk:"private_key"`
+	BaseURL        types.String `tfsdk:"base_url"`
+}
+
+type PullRequest struct {
+	Provider     types.String `tfsdk:"provider"`
+	APIURL       types.String `tfsdk:"api_url"`
+	Token        types.String `tfsdk:"token"`
+	BranchPrefix types.String `tfsdk:"branch_prefix"`
+	WaitForMerge types.Bool   `tfsdk:"wait_for_merge"`
+}
+
 type Git struct {
 	Url                   customtypes.URL `tfsdk:"url"`
 	Branch                types.String    `tfsdk:"branch"`
+	BaseBranch            types.String    `tfsdk:"base_branch"`
 	AuthorName            types.String    `tfsdk:"author_name"`
 	AuthorEmail           types.String    `tfsdk:"author_email"`
 	GpgKeyRing            types.String    `tfsdk:"gpg_key_ring"`
+	GpgKeyRingContent     types.String    `tfsdk:"gpg_key_ring_content"`
 	GpgPassphrase         types.String    `tfsdk:"gpg_passphrase"`
 	GpgKeyID              types.String    `tfsdk:"gpg_key_id"`
 	CommitMessageAppendix types.String    `tfsdk:"commit_message_appendix"`
 	Ssh                   *Ssh            `tfsdk:"ssh"`
 	Http                  *Http           `tfsdk:"http"`
+	GitHubApp             *GitHubApp      `tfsdk:"github_app"`
+	PullRequest           *PullRequest    `tfsdk:"pull_request"`
+	Proxy                 *Proxy          `tfsdk:"proxy"`
+	Signing               *Signing        `tfsdk:"signing"`
+}
+
+// clone returns a copy of the Git configuration, which can be changed without changing the original.
+func (g *Git) clone() *Git {
+	if g == nil {
+		return nil
+	}
+	c := *g
+	if g.Ssh != nil {
+		ssh := *g.Ssh
+		c.Ssh = &ssh
+	}
+	if g.Http != nil {
+		h := *g.Http
+		c.Http = &h
+	}
+	if g.GitHubApp != nil {
+		app := *g.GitHubApp
+		c.GitHubApp = &app
+	}
+	if g.PullRequest != nil {
+		pr := *g.PullRequest
+		c.PullRequest = &pr
+	}
+	if g.Proxy != nil {
+		proxy := *g.Proxy
+		c.Proxy = &proxy
+	}
+	if g.Signing != nil {
+		signing := *g.Signing
+		c.Signing = &signing
+	}
+	return &c
 }
 
 type KubernetesExec struct {
@@ -79,21 +165,42 @@ type KubernetesExec struct {
 }
 
 type Kubernetes struct {
-	Host                  types.String    `tfsdk:"host"`
-	Username              types.String    `tfsdk:"username"`
-	Password              types.String    `tfsdk:"password"`
-	Insecure              types.Bool      `tfsdk:"insecure"`
-	ClientCertificate     types.String    `tfsdk:"client_certificate"`
-	ClientKey             types.String    `tfsdk:"client_key"`
-	ClusterCACertificate  types.String    `tfsdk:"cluster_ca_certificate"`
-	ConfigPaths           types.Set       `tfsdk:"config_paths"`
-	ConfigPath            types.String    `tfsdk:"config_path"`
-	ConfigContext         types.String    `tfsdk:"config_context"`
-	ConfigContextAuthInfo types.String    `tfsdk:"config_context_auth_info"`
-	ConfigContextCluster  types.String    `tfsdk:"config_context_cluster"`
-	Token                 types.String    `tfsdk:"token"`
-	ProxyURL              types.String    `tfsdk:"proxy_url"`
-	Exec                  *KubernetesExec `tfsdk:"exec"`
+	Host                  types.String         `tfsdk:"host"`
+	Username              types.String         `tfsdk:"username"`
+	Password              types.String         `tfsdk:"password"`
+	Insecure              types.Bool           `tfsdk:"insecure"`
+	ClientCertificate     types.String         `tfsdk:"client_certificate"`
+	ClientKey             types.String         `tfsdk:"client_key"`
+	ClusterCACertificate  types.String         `tfsdk:"cluster_ca_certificate"`
+	ConfigPaths           types.Set            `tfsdk:"config_paths"`
+	ConfigPath            types.String         `tfsdk:"config_path"`
+	ConfigContext         types.String         `tfsdk:"config_context"`
+	ConfigContextAuthInfo types.String         `tfsdk:"config_context_auth_info"`
+	ConfigContextCluster  types.String         `tfsdk:"config_context_cluster"`
+	Token                 types.String         `tfsdk:"token"`
+	ProxyURL              types.String         `tfsdk:"proxy_url"`
+	TLSServerName         types.String         `tfsdk:"tls_server_name"`
+	As                    types.String         `tfsdk:"as"`
+	AsGroups              types.List           `tfsdk:"as_groups"`
+	AsUID                 types.String         `tfsdk:"as_uid"`
+	InCluster             types.Bool           `tfsdk:"in_cluster"`
+	QPS                   types.Float64        `tfsdk:"qps"`
+	Burst                 types.Int64          `tfsdk:"burst"`
+	RequestTimeout        customtypes.Duration `tfsdk:"request_timeout"`
+	Exec                  *KubernetesExec      `tfsdk:"exec"`
+}
+
+// clone returns a copy of the Kubernetes configuration, which can be changed without changing the original.
+func (k *Kubernetes) clone() *Kubernetes {
+	if k == nil {
+		return nil
+	}
+	c := *k
+	if k.Exec != nil {
+		exec := *k.Exec
+		c.Exec = &exec
+	}
+	return &c
 }
 
 type ProviderModel struct {
@@ -122,7 +229,12 @@ func (p *fluxProvider) Metadata(ctx context.Context, req provider.MetadataReques
 }
 
 func (p *fluxProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
-	resp.Schema = schema.Schema{
+	resp.Schema = providerSchema()
+}
+
+// providerSchema returns the schema of the provider, the git and kubernetes attributes are also used by resources to override it.
+func providerSchema() schema.Schema {
+	return schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"kubernetes": schema.SingleNestedAttribute{
 				Description: "Configuration block with settings for Kubernetes.",
@@ -138,6 +250,7 @@ func (p *fluxProvider) Schema(ctx context.Context, req provider.SchemaRequest, r
 					"password": schema.StringAttribute{
 						Optional:    true,
 						Description: "The password to use for HTTP basic authentication when accessing the Kubernetes master endpoint.",
+						Sensitive:   true,
 					},
 					"insecure": schema.BoolAttribute{
 						Optional:    true,
@@ -150,6 +263,7 @@ func (p *fluxProvider) Schema(ctx context.Context, req provider.SchemaRequest, r
 					"client_key": schema.StringAttribute{
 						Optional:    true,
 						Description: "PEM-encoded client certificate key for TLS authentication.",
+						Sensitive:   true,
 					},
 					"cluster_ca_certificate": schema.StringAttribute{
 						Optional:    true,
@@ -179,11 +293,53 @@ func (p *fluxProvider) Schema(ctx context.Context, req provider.SchemaRequest, r
 					"token": schema.StringAttribute{
 						Optional:    true,
 						Description: "Token to authenticate an service account.",
+						Sensitive:   true,
 					},
 					"proxy_url": schema.StringAttribute{
 						Optional:    true,
 						Description: "URL to the proxy to be used for all API requests.",
 					},
+					"tls_server_name": schema.StringAttribute{
+						Optional:    true,
+						Description: "Server name used to verify the TLS certificate of the Kubernetes master, which is also sent as SNI when connecting through a proxy or load balancer.",
+					},
+					"as": schema.StringAttribute{
+						Optional:    true,
+						Description: "Username to impersonate for the operations (`--as` flag in `kubectl`).",
+					},
+					"as_groups": schema.ListAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Description: "Groups to impersonate for the operations (`--as-group` flag in `kubectl`). Requires `as` to be set.",
+					},
+					"as_uid": schema.StringAttribute{
+						Optional:    true,
+						Description: "UID to impersonate for the operations (`--as-uid` flag in `kubectl`). Requires `as` to be set.",
+					},
+					"in_cluster": schema.BoolAttribute{
+						Optional: true,
+						Description: "Use the service account token and CA certificate mounted into the pod running Terraform. " +
+							"Defaults to `true` when running in a pod and no kube config, host, credentials or exec plugin are configured.",
+					},
+					"qps": schema.Float64Attribute{
+						Optional:    true,
+						Description: "Maximum queries per second to the Kubernetes API server. Requires `burst` to be set. Defaults to the client-go defaults.",
+						Validators: []validator.Float64{
+							float64validator.AtLeast(0),
+						},
+					},
+					"burst": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Maximum burst of queries to the Kubernetes API server, for throttling beyond `qps`. Defaults to the client-go defaults.",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"request_timeout": schema.StringAttribute{
+						CustomType:  customtypes.DurationType{},
+						Optional:    true,
+						Description: "Timeout of a single request to the Kubernetes API server, e.g. `30s`. Not set by default.",
+					},
 					"exec": schema.SingleNestedAttribute{
 						Attributes: map[string]schema.Attribute{
 							"api_version": schema.StringAttribute{
@@ -226,6 +382,10 @@ func (p *fluxProvider) Schema(ctx context.Context, req provider.SchemaRequest, r
 						Description: fmt.Sprintf("Branch of the repository to reconcile from. Defaults to `%s`.", defaultBranch),
 						Optional:    true,
 					},
+					"base_branch": schema.StringAttribute{
+						Description: "Branch used as the base when the branch to reconcile from does not exist yet. When not set, a missing branch is created with an initial commit.",
+						Optional:    true,
+					},
 					"author_name": schema.StringAttribute{
 						Description: fmt.Sprintf("Author name for Git commits. Defaults to `%s`.", defaultAuthor),
 						Optional:    true,
@@ -238,6 +398,11 @@ func (p *fluxProvider) Schema(ctx context.Context, req provider.SchemaRequest, r
 						Description: "Path to the GPG key ring for signing commits.",
 						Optional:    true,
 					},
+					"gpg_key_ring_content": schema.StringAttribute{
+						Description: "GPG key ring for signing commits, either ASCII armored or base64 encoded binary. Conflicts with `gpg_key_ring`.",
+						Optional:    true,
+						Sensitive:   true,
+					},
 					"gpg_passphrase": schema.StringAttribute{
 						Description: "Passphrase for decrypting GPG private key.",
 						Optional:    true,
@@ -247,6 +412,29 @@ func (p *fluxProvider) Schema(ctx context.Context, req provider.SchemaRequest, r
 						Description: "Key id for selecting a particular GPG key.",
 						Optional:    true,
 					},
+					"signing": schema.SingleNestedAttribute{
+						Description: "Signing of the commits created by the provider.",
+						Attributes: map[string]schema.Attribute{
+							"format": schema.StringAttribute{
+								Description: fmt.Sprintf("Format of the commit signatures. Must be one of `%s` or `%s`, `%s` signs with `gpg_key_ring` or `gpg_key_ring_content` and `%s` with `private_key`.", signingFormatGPG, signingFormatSSH, signingFormatGPG, signingFormatSSH),
+								Required:    true,
+								Validators: []validator.String{
+									stringvalidator.OneOf(signingFormatGPG, signingFormatSSH),
+								},
+							},
+							"private_key": schema.StringAttribute{
+								Description: "SSH private key for signing commits, required when the format is `ssh`.",
+								Optional:    true,
+								Sensitive:   true,
+							},
+							"passphrase": schema.StringAttribute{
+								Description: "Passphrase for decrypting the SSH private key.",
+								Optional:    true,
+								Sensitive:   true,
+							},
+						},
+						Optional: true,
+					},
 					"commit_message_appendix": schema.StringAttribute{
 						Description: "String to add to the commit messages.",
 						Optional:    true,
@@ -272,6 +460,10 @@ func (p *fluxProvider) Schema(ctx context.Context, req provider.SchemaRequest, r
 								Description: "The list of hostkey algorithms to use for ssh connections, arranged from most preferred to the least.",
 								Optional:    true,
 							},
+							"known_hosts": schema.StringAttribute{
+								Description: fmt.Sprintf("Known hosts entries used to verify the Git SSH server, in the OpenSSH known_hosts format. The host key is scanned at runtime when not set. Can be set with %s environment variable.", gitSSHKnownHostsEnvVar),
+								Optional:    true,
+							},
 						},
 						Optional: true,
 					},
@@ -286,6 +478,11 @@ func (p *fluxProvider) Schema(ctx context.Context, req provider.SchemaRequest, r
 								Optional:    true,
 								Sensitive:   true,
 							},
+							"bearer_token": schema.StringAttribute{
+								Description: "Token for bearer authentication, it is also written as `bearerToken` to the sync secret. Conflicts with `username` and `password`.",
+								Optional:    true,
+								Sensitive:   true,
+							},
 							"allow_insecure_http": schema.BoolAttribute{
 								Description: "Allows http Git url connections.",
 								Optional:    true,
@@ -297,6 +494,91 @@ func (p *fluxProvider) Schema(ctx context.Context, req provider.SchemaRequest, r
 						},
 						Optional: true,
 					},
+					"github_app": schema.SingleNestedAttribute{
+						Description: "Authenticate to GitHub as a GitHub App installation. Short-lived installation tokens are used for Git operations and the sync secret is created with the GitHub App credentials.",
+						Attributes: map[string]schema.Attribute{
+							"app_id": schema.StringAttribute{
+								Description: "ID of the GitHub App.",
+								Required:    true,
+							},
+							"installation_id": schema.StringAttribute{
+								Description: "ID of the GitHub App installation.",
+								Required:    true,
+							},
+							"private_key": schema.StringAttribute{
+								Description: "PEM encoded private key of the GitHub App.",
+								Required:    true,
+								Sensitive:   true,
+							},
+							"base_url": schema.StringAttribute{
+								Description: "Base URL of the GitHub API. Defaults to `https://api.github.com`.",
+								Optional:    true,
+							},
+						},
+						Optional: true,
+					},
+					"pull_request": schema.SingleNestedAttribute{
+						Description: "Push manifest changes made when updating or deleting `flux_bootstrap_git` to a new branch and open a pull request against `branch` instead of pushing to it directly. Creating the resource still pushes to `branch` directly, as Flux is bootstrapped from it.",
+						Attributes: map[string]schema.Attribute{
+							"provider": schema.StringAttribute{
+								Description: "Git hosting service API used to open pull requests. Must be one of `github`, `gitlab` or `gitea`.",
+								Required:    true,
+								Validators: []validator.String{
+									stringvalidator.OneOf(forge.Providers...),
+								},
+							},
+							"api_url": schema.StringAttribute{
+								Description: "Base URL of the Git hosting service API. Derived from the repository URL when not set.",
+								Optional:    true,
+							},
+							"token": schema.StringAttribute{
+								Description: "Token used to authenticate to the Git hosting service API. Defaults to `http.password` or a GitHub App installation token when `github_app` is configured.",
+								Optional:    true,
+								Sensitive:   true,
+							},
+							"branch_prefix": schema.StringAttribute{
+								Description: fmt.Sprintf("Prefix of the branches created for pull requests, a timestamp is appended to it. Defaults to `%s`.", defaultPullRequestBranchPrefix),
+								Optional:    true,
+							},
+							"wait_for_merge": schema.BoolAttribute{
+								Description: "Wait for the pull request to be merged before applying the changes to the cluster. When false, the changes are applied by Flux once the pull request is merged, and deleting `flux_bootstrap_git` with the `uninstall` deletion policy leaves Flux installed in the cluster.",
+								Optional:    true,
+							},
+						},
+						Optional: true,
+					},
+					"proxy": schema.SingleNestedAttribute{
+						Description: "Proxy used for Git operations and Git hosting service API calls.",
+						Attributes: map[string]schema.Attribute{
+							"url": schema.StringAttribute{
+								CustomType:  customtypes.URLType{},
+								Description: "Url of the HTTP or SOCKS5 proxy, SSH remotes require a SOCKS5 proxy.",
+								Required:    true,
+								Validators: []validator.String{
+									validators.URLScheme(httpScheme, "https", "socks5"),
+								},
+							},
+							"username": schema.StringAttribute{
+								Description: "Username for proxy authentication.",
+								Optional:    true,
+							},
+							"password": schema.StringAttribute{
+								Description: "Password for proxy authentication.",
+								Optional:    true,
+								Sensitive:   true,
+							},
+							"no_proxy": schema.ListAttribute{
+								ElementType: types.StringType,
+								Description: "Hosts, domains and CIDRs the repository is reached without proxy from, using the `NO_PROXY` syntax.",
+								Optional:    true,
+							},
+							"secret_name": schema.StringAttribute{
+								Description: "Name of a secret created with the proxy address and credentials in the Flux namespace. When set, the root GitRepository references it as `spec.proxySecretRef`.",
+								Optional:    true,
+							},
+						},
+						Optional: true,
+					},
 				},
 				Optional: true,
 			},
@@ -311,82 +593,309 @@ func (p *fluxProvider) ValidateConfig(ctx context.Context, req provider.Validate
 		return
 	}
 
-	if data.Git != nil && data.Git.Url.ValueURL() != nil {
-		if data.Git.Url.ValueURL().Scheme == "ssh" && data.Git.Http != nil {
-			resp.Diagnostics.AddAttributeError(
+	if data.Kubernetes != nil {
+		resp.Diagnostics.Append(validateKubernetes(data.Kubernetes)...)
+	}
+	if data.Git != nil {
+		resp.Diagnostics.Append(validateGit(data.Git)...)
+	}
+}
+
+// validateKubernetes validates the Kubernetes configuration of the provider and of the resources overriding it.
+func validateKubernetes(k *Kubernetes) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if k.InCluster.ValueBool() {
+		for _, name := range kubernetesConnectionAttributes(k) {
+			diags.AddAttributeError(
+				path.Root("kubernetes").AtName(name),
+				"Conflicting Attribute Configuration",
+				fmt.Sprintf("Did not expect %s to be configured when in_cluster is true.", name),
+			)
+		}
+	}
+
+	// Impersonating groups or a UID without a user is rejected by client-go when building the transport.
+	if k.As.IsNull() {
+		if !k.AsGroups.IsNull() {
+			diags.AddAttributeError(
+				path.Root("kubernetes").AtName("as_groups"),
+				"Missing Attribute Configuration",
+				"Expected as to be configured when as_groups is set.",
+			)
+		}
+		if !k.AsUID.IsNull() {
+			diags.AddAttributeError(
+				path.Root("kubernetes").AtName("as_uid"),
+				"Missing Attribute Configuration",
+				"Expected as to be configured when as_uid is set.",
+			)
+		}
+	} else if !k.As.IsUnknown() && k.As.ValueString() == "" {
+		diags.AddAttributeError(
+			path.Root("kubernetes").AtName("as"),
+			"Invalid Attribute Configuration",
+			"Expected as to be a non-empty username.",
+		)
+	}
+
+	// A rate limiter allowing queries without a burst is rejected by client-go.
+	if !k.QPS.IsNull() && k.QPS.ValueFloat64() > 0 && k.Burst.IsNull() {
+		diags.AddAttributeError(
+			path.Root("kubernetes").AtName("burst"),
+			"Missing Attribute Configuration",
+			"Expected burst to be configured when qps is set.",
+		)
+	}
+
+	if !k.TLSServerName.IsNull() && k.Insecure.ValueBool() {
+		diags.AddAttributeError(
+			path.Root("kubernetes").AtName("tls_server_name"),
+			"Conflicting Attribute Configuration",
+			"Did not expect tls_server_name to be configured when insecure is true, as the certificate is not verified.",
+		)
+	}
+	return diags
+}
+
+// kubernetesConnectionAttributes returns the names of the configured attributes which select the cluster
+// or the credentials, and which can therefore not be combined with the in-cluster configuration.
+func kubernetesConnectionAttributes(k *Kubernetes) []string {
+	var names []string
+	for name, v := range map[string]attr.Value{
+		"host":                     k.Host,
+		"username":                 k.Username,
+		"password":                 k.Password,
+		"insecure":                 k.Insecure,
+		"client_certificate":       k.ClientCertificate,
+		"client_key":               k.ClientKey,
+		"cluster_ca_certificate":   k.ClusterCACertificate,
+		"config_paths":             k.ConfigPaths,
+		"config_path":              k.ConfigPath,
+		"config_context":           k.ConfigContext,
+		"config_context_auth_info": k.ConfigContextAuthInfo,
+		"config_context_cluster":   k.ConfigContextCluster,
+		"token":                    k.Token,
+	} {
+		if !v.IsNull() {
+			names = append(names, name)
+		}
+	}
+	if k.Exec != nil {
+		names = append(names, "exec")
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateGit validates the Git configuration of the provider and of the resources overriding it.
+func validateGit(g *Git) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if g.Url.ValueURL() != nil {
+		if g.Url.ValueURL().Scheme == "ssh" && g.Http != nil {
+			diags.AddAttributeError(
 				path.Root("git.http"),
 				"Unexpected Attribute Configuration",
 				"Did not expect http to be configured when url scheme is ssh",
 			)
 		}
 
-		if (data.Git.Url.ValueURL().Scheme == httpScheme || data.Git.Url.ValueURL().Scheme == "https") && data.Git.Ssh != nil {
-			resp.Diagnostics.AddAttributeError(
+		if (g.Url.ValueURL().Scheme == httpScheme || g.Url.ValueURL().Scheme == "https") && g.Ssh != nil {
+			diags.AddAttributeError(
 				path.Root("git.ssh"),
 				"Unexpected Attribute Configuration",
 				"Did not expect ssh to be configured when url scheme is http(s)",
 			)
 		}
 
-		if data.Git.Url.ValueURL().Scheme == httpScheme && !data.Git.Http.InsecureHttpAllowed.ValueBool() {
-			resp.Diagnostics.AddAttributeError(
+		if g.Url.ValueURL().Scheme == httpScheme && (g.Http == nil || !g.Http.InsecureHttpAllowed.ValueBool()) {
+			diags.AddAttributeError(
 				path.Root("git.allow_insecure_http"),
 				"Scheme Validation Error",
 				"Expected allow_insecure_http to be true when url scheme is http.",
 			)
 		}
 	}
-}
 
-func (p *fluxProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
-	var data ProviderModel
-	diags := req.Config.Get(ctx, &data)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+	if g.Http != nil && !g.Http.BearerToken.IsNull() {
+		if !g.Http.Username.IsNull() || !g.Http.Password.IsNull() {
+			diags.AddAttributeError(
+				path.Root("git").AtName("http").AtName("bearer_token"),
+				"Conflicting Attribute Configuration",
+				"Did not expect username or password to be configured when bearer_token is configured.",
+			)
+		}
 	}
 
-	// Either Git and Kubernetes configuration is set or none of them are set.
-	// An error is returned if either or is set.
-	if data.Git == nil && data.Kubernetes == nil {
-		return
-	}
-	if data.Git == nil && data.Kubernetes != nil {
-		resp.Diagnostics.AddError("Git configuration is empty when Kubernetes is not", "Either none or both Git and Kubernetes blocks need to be set")
-		return
+	if g.GitHubApp != nil {
+		if g.Url.ValueURL() != nil && g.Url.ValueURL().Scheme != "https" {
+			diags.AddAttributeError(
+				path.Root("git").AtName("github_app"),
+				"Unexpected Attribute Configuration",
+				"Expected url scheme to be https when github_app is configured.",
+			)
+		}
+		if g.Http != nil && (!g.Http.Username.IsNull() || !g.Http.Password.IsNull() || !g.Http.BearerToken.IsNull()) {
+			diags.AddAttributeError(
+				path.Root("git").AtName("github_app"),
+				"Conflicting Attribute Configuration",
+				"Did not expect http username, password or bearer_token to be configured when github_app is configured.",
+			)
+		}
+		if g.GitHubApp.PrivateKey.ValueString() != "" {
+			if _, err := forge.ParseGitHubAppPrivateKey([]byte(g.GitHubApp.PrivateKey.ValueString())); err != nil {
+				diags.AddAttributeError(
+					path.Root("git").AtName("github_app").AtName("private_key"),
+					"Invalid GitHub App private key",
+					err.Error(),
+				)
+			}
+		}
 	}
-	if data.Git != nil && data.Kubernetes == nil {
-		resp.Diagnostics.AddError("Kubernetes configuration is empty when Git is not", "Either none or both Git and Kubernetes blocks need to be set")
-		return
+
+	if g.PullRequest != nil && g.PullRequest.Token.IsNull() && g.GitHubApp == nil {
+		if g.Http == nil || g.Http.Password.IsNull() {
+			diags.AddAttributeError(
+				path.Root("git").AtName("pull_request").AtName("token"),
+				"Missing Attribute Configuration",
+				"Expected token to be configured when neither http.password nor github_app is set.",
+			)
+		}
 	}
 
-	// Set default values.
-	if data.Git.Branch.IsNull() {
-		data.Git.Branch = types.StringValue(defaultBranch)
+	// go-git only dials SSH remotes through socks5 proxies, http(s) proxies would be ignored.
+	if g.Proxy != nil && g.Proxy.Url.ValueURL() != nil && g.Url.ValueURL() != nil && g.Url.ValueURL().Scheme == "ssh" {
+		if proxyURL, err := getProxyURL(g.Proxy, g.Url.ValueURL()); err == nil && proxyURL != nil && proxyURL.Scheme != "socks5" {
+			diags.AddAttributeError(
+				path.Root("git").AtName("proxy").AtName("url"),
+				"Invalid URL scheme",
+				"Expected proxy url scheme to be socks5 when url scheme is ssh.",
+			)
+		}
 	}
-	if data.Git.AuthorName.IsNull() {
-		data.Git.AuthorName = types.StringValue(defaultAuthor)
+
+	if g.Proxy != nil && g.Proxy.Username.IsNull() && !g.Proxy.Password.IsNull() {
+		diags.AddAttributeError(
+			path.Root("git").AtName("proxy").AtName("username"),
+			"Missing Attribute Configuration",
+			"Expected username to be configured when password is set.",
+		)
 	}
-	if data.Kubernetes.ConfigPath.IsNull() {
-		if v, ok := os.LookupEnv("KUBE_CONFIG_PATH"); ok {
-			data.Kubernetes.ConfigPath = types.StringValue(v)
+
+	if !g.GpgKeyRingContent.IsNull() {
+		if !g.GpgKeyRing.IsNull() {
+			diags.AddAttributeError(
+				path.Root("git").AtName("gpg_key_ring_content"),
+				"Conflicting Attribute Configuration",
+				"Did not expect gpg_key_ring to be configured when gpg_key_ring_content is set.",
+			)
+		}
+		// Values known only after apply, e.g. from other resources, are validated when the key ring is used.
+		if !g.GpgKeyRingContent.IsUnknown() && !g.GpgPassphrase.IsUnknown() && !g.GpgKeyID.IsUnknown() {
+			entityList, err := readGpgKeyRing(g.GpgKeyRingContent.ValueString())
+			if err == nil {
+				_, err = getOpenPgpEntity(entityList, g.GpgPassphrase.ValueString(), g.GpgKeyID.ValueString())
+			}
+			if err != nil {
+				diags.AddAttributeError(
+					path.Root("git").AtName("gpg_key_ring_content"),
+					"Invalid GPG key ring",
+					err.Error(),
+				)
+			}
 		}
 	}
-	if data.Kubernetes.ConfigPaths.IsNull() {
-		if v, ok := os.LookupEnv("KUBE_CONFIG_PATHS"); ok {
-			var paths []attr.Value
-			for _, p := range filepath.SplitList(v) {
-				paths = append(paths, types.StringValue(p))
+
+	if g.Signing != nil {
+		switch g.Signing.Format.ValueString() {
+		case signingFormatGPG:
+			if g.GpgKeyRing.IsNull() && g.GpgKeyRingContent.IsNull() {
+				diags.AddAttributeError(
+					path.Root("git").AtName("gpg_key_ring"),
+					"Missing Attribute Configuration",
+					"Expected gpg_key_ring or gpg_key_ring_content to be configured when the signing format is gpg.",
+				)
+			}
+			if !g.Signing.PrivateKey.IsNull() {
+				diags.AddAttributeError(
+					path.Root("git").AtName("signing").AtName("private_key"),
+					"Unexpected Attribute Configuration",
+					"Did not expect private_key to be configured when the signing format is gpg.",
+				)
+			}
+		case signingFormatSSH:
+			if !g.GpgKeyRing.IsNull() || !g.GpgKeyRingContent.IsNull() {
+				diags.AddAttributeError(
+					path.Root("git").AtName("gpg_key_ring"),
+					"Conflicting Attribute Configuration",
+					"Did not expect gpg_key_ring or gpg_key_ring_content to be configured when the signing format is ssh.",
+				)
 			}
-			data.Kubernetes.ConfigPaths = types.SetValueMust(types.StringType, paths)
+			if g.Signing.PrivateKey.IsNull() {
+				diags.AddAttributeError(
+					path.Root("git").AtName("signing").AtName("private_key"),
+					"Missing Attribute Configuration",
+					"Expected private_key to be configured when the signing format is ssh.",
+				)
+			} else if g.Signing.PrivateKey.ValueString() != "" && !g.Signing.Passphrase.IsUnknown() {
+				_, err := sshsig.NewSigner([]byte(g.Signing.PrivateKey.ValueString()), []byte(g.Signing.Passphrase.ValueString()), sshsig.NamespaceGit)
+				if err != nil {
+					diags.AddAttributeError(
+						path.Root("git").AtName("signing").AtName("private_key"),
+						"Invalid SSH signing key",
+						err.Error(),
+					)
+				}
+			}
+		}
+	}
+
+	if g.Ssh != nil && g.Ssh.KnownHosts.ValueString() != "" {
+		if _, err := knownhosts.ParseKnownHosts(g.Ssh.KnownHosts.ValueString()); err != nil {
+			diags.AddAttributeError(
+				path.Root("git").AtName("ssh").AtName("known_hosts"),
+				"Invalid known_hosts",
+				fmt.Sprintf("Could not parse known_hosts: %s", err),
+			)
 		}
 	}
+	return diags
+}
+
+func (p *fluxProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	// Values referring to resources created in the same plan, like the endpoint of a new cluster, are
+	// unknown during plan. The clients are created when applying, where the provider is configured again.
+	if !req.Config.Raw.IsFullyKnown() {
+		resp.ResourceData = &providerResourceData{unknown: true}
+		return
+	}
+
+	var data ProviderModel
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	if data.Git.Ssh != nil && !data.Git.Ssh.HostKeyAlgos.IsNull() && len(data.Git.Ssh.HostKeyAlgos.Elements()) > 0 {
+	// The Kubernetes configuration defaults to the in-cluster configuration when running in a pod.
+	setProviderDefaults(ctx, &data)
+
+	// Git and Kubernetes configuration can be set on their own, resources
+	// which require both can set the missing configuration themselves.
+	if data.Git == nil && data.Kubernetes == nil {
+		return
+	}
+
+	// The host key algorithms are global to the Git clients, they can only be configured by the provider.
+	if data.Git != nil && data.Git.Ssh != nil && !data.Git.Ssh.HostKeyAlgos.IsNull() && len(data.Git.Ssh.HostKeyAlgos.Elements()) > 0 {
 		elements := make([]types.String, 0, len(data.Git.Ssh.HostKeyAlgos.Elements()))
 		data.Git.Ssh.HostKeyAlgos.ElementsAs(ctx, &elements, false)
 		for _, algo := range elements {
-			git.HostKeyAlgos = append(git.HostKeyAlgos, algo.ValueString())
+			// The provider is configured more than once per run, e.g. for plan and apply.
+			if !slices.Contains(git.HostKeyAlgos, algo.ValueString()) {
+				git.HostKeyAlgos = append(git.HostKeyAlgos, algo.ValueString())
+			}
 		}
 	}
 
@@ -398,12 +907,74 @@ func (p *fluxProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 	resp.ResourceData = prd
 }
 
+// setProviderDefaults sets the default values of the provider configuration, which are also
+// applied to the configuration overrides of resources.
+func setProviderDefaults(ctx context.Context, data *ProviderModel) {
+	if data.Git != nil {
+		if data.Git.Branch.IsNull() {
+			data.Git.Branch = types.StringValue(defaultBranch)
+		}
+		if data.Git.AuthorName.IsNull() {
+			data.Git.AuthorName = types.StringValue(defaultAuthor)
+		}
+		if data.Git.PullRequest != nil {
+			if data.Git.PullRequest.Token.IsNull() && data.Git.Http != nil {
+				data.Git.PullRequest.Token = data.Git.Http.Password
+			}
+			if data.Git.PullRequest.BranchPrefix.IsNull() {
+				data.Git.PullRequest.BranchPrefix = types.StringValue(defaultPullRequestBranchPrefix)
+			}
+		}
+	}
+	if data.Kubernetes == nil {
+		if _, ok := os.LookupEnv("KUBERNETES_SERVICE_HOST"); ok {
+			data.Kubernetes = &Kubernetes{
+				ConfigPaths: types.SetNull(types.StringType),
+				AsGroups:    types.ListNull(types.StringType),
+			}
+		}
+	}
+	if data.Kubernetes != nil && !data.Kubernetes.InCluster.ValueBool() {
+		if data.Kubernetes.ConfigPath.IsNull() {
+			if v, ok := os.LookupEnv("KUBE_CONFIG_PATH"); ok {
+				data.Kubernetes.ConfigPath = types.StringValue(v)
+			}
+		}
+		if data.Kubernetes.ConfigPaths.IsNull() {
+			if v, ok := os.LookupEnv("KUBE_CONFIG_PATHS"); ok {
+				var paths []attr.Value
+				for _, p := range filepath.SplitList(v) {
+					paths = append(paths, types.StringValue(p))
+				}
+				data.Kubernetes.ConfigPaths = types.SetValueMust(types.StringType, paths)
+			}
+		}
+	}
+	// Terraform running in a pod, e.g. in Atlantis or tf-controller, uses the mounted service account
+	// when nothing else is configured. KUBERNETES_SERVICE_HOST is set by the kubelet in every container.
+	if data.Kubernetes != nil && data.Kubernetes.InCluster.IsNull() {
+		_, ok := os.LookupEnv("KUBERNETES_SERVICE_HOST")
+		data.Kubernetes.InCluster = types.BoolValue(ok && len(kubernetesConnectionAttributes(data.Kubernetes)) == 0)
+	}
+
+	if data.Git != nil && data.Git.Ssh != nil && data.Git.Ssh.KnownHosts.IsNull() {
+		if v, ok := os.LookupEnv(gitSSHKnownHostsEnvVar); ok {
+			data.Git.Ssh.KnownHosts = types.StringValue(v)
+		}
+	}
+}
+
 func (p *fluxProvider) DataSources(context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewInstallManifestsDataSource,
+		NewSyncManifestsDataSource,
+	}
 }
 
 func (p *fluxProvider) Resources(context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewBootstrapGitResource,
+		NewBootstrapOCIResource,
+		NewInstallResource,
 	}
 }
@@ -19,9 +19,15 @@ package provider
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/fluxcd/flux2/v2/pkg/bootstrap"
@@ -32,46 +38,110 @@ import (
 	"github.com/fluxcd/pkg/git/gogit"
 	"github.com/fluxcd/pkg/git/repository"
 	runclient "github.com/fluxcd/pkg/runtime/client"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/mitchellh/go-homedir"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"golang.org/x/net/http/httpproxy"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apimachineryschema "k8s.io/apimachinery/pkg/runtime/schema"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
+	"github.com/fluxcd/terraform-provider-flux/internal/forge"
+	"github.com/fluxcd/terraform-provider-flux/internal/sshsig"
 	"github.com/fluxcd/terraform-provider-flux/internal/utils"
 )
 
+const (
+	// gitHubAppUsername is the username used with GitHub App installation tokens.
+	gitHubAppUsername = "x-access-token"
+
+	// bearerTokenSecretKey is the key of the token used for bearer authentication in the sync secret.
+	bearerTokenSecretKey = "bearerToken"
+
+	// Keys of the GitHub App credentials in the secret referenced by a GitRepository with the github provider.
+	gitHubAppIDSecretKey             = "githubAppID"
+	gitHubAppInstallationIDSecretKey = "githubAppInstallationID"
+	gitHubAppPrivateKeySecretKey     = "githubAppPrivateKey"
+	gitHubAppBaseURLSecretKey        = "githubAppBaseURL"
+
+	// Keys of the secret referenced by spec.proxySecretRef of a GitRepository.
+	proxyAddressSecretKey  = "address"
+	proxyUsernameSecretKey = "username"
+	proxyPasswordSecretKey = "password"
+
+	// Name of the cluster, user and context of the kubeconfig created for the in-cluster configuration.
+	inClusterContext = "in-cluster"
+)
+
 type providerResourceData struct {
-	rcg *utils.RESTClientGetter
-	git *Git
+	rcg        *utils.RESTClientGetter
+	clientOpts *runclient.Options
+	git        *Git
+	kubernetes *Kubernetes
+	// unknown is set when the provider configuration contains values which are unknown during plan.
+	unknown bool
 }
 
 func NewProviderResourceData(ctx context.Context, data ProviderModel) (*providerResourceData, error) {
-	clientCfg, err := getClientConfiguration(ctx, data.Kubernetes)
-	if err != nil {
-		return nil, fmt.Errorf("invalid Kubernetes configuration: %w", err)
+	prd := &providerResourceData{
+		git:        data.Git,
+		kubernetes: data.Kubernetes,
 	}
-	rcg := utils.NewRestClientGetter(clientCfg)
-	return &providerResourceData{
-		rcg: rcg,
-		git: data.Git,
-	}, nil
+	// The Kubernetes configuration may be set by the resources instead.
+	if data.Kubernetes != nil {
+		clientCfg, err := getClientConfiguration(ctx, data.Kubernetes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Kubernetes configuration: %w", err)
+		}
+		prd.clientOpts = &runclient.Options{
+			QPS:   float32(data.Kubernetes.QPS.ValueFloat64()),
+			Burst: int(data.Kubernetes.Burst.ValueInt64()),
+		}
+		prd.rcg = utils.NewRestClientGetter(clientCfg, prd.clientOpts)
+	}
+	return prd, nil
+}
 
+// WithOverrides returns the resource data with the Git and Kubernetes configuration of the provider replaced
+// by the configuration set on a resource. The receiver is nil when the provider itself is not configured.
+func (prd *providerResourceData) WithOverrides(ctx context.Context, g *Git, kubernetes *Kubernetes) (*providerResourceData, error) {
+	if g == nil && kubernetes == nil {
+		return prd, nil
+	}
+	// The defaults are set on copies, the configuration is shared with the provider and the resource data.
+	data := ProviderModel{Git: g.clone(), Kubernetes: kubernetes.clone()}
+	if prd != nil && data.Git == nil {
+		data.Git = prd.git.clone()
+	}
+	if prd != nil && data.Kubernetes == nil {
+		data.Kubernetes = prd.kubernetes.clone()
+	}
+	setProviderDefaults(ctx, &data)
+	return NewProviderResourceData(ctx, data)
 }
 
 func (prd *providerResourceData) GetKubernetesClient() (client.WithWatch, error) {
 	if prd.rcg == nil {
 		return nil, fmt.Errorf("kubernetes client cannot be created without any Kubernetes provider configuration")
 	}
-	kubeClient, err := utils.KubeClient(prd.rcg, &runclient.Options{})
+	kubeClient, err := utils.KubeClient(prd.rcg, prd.clientOpts)
 	if err != nil {
 		return nil, err
 	}
 	return kubeClient, nil
 }
 
-func (prd *providerResourceData) GetGitClient(tmpDir string) (*gogit.Client, error) {
+func (prd *providerResourceData) GetGitClient(tmpDir string) (*signingGitClient, error) {
 	authOpts, err := getAuthOpts(prd.git)
 	if err != nil {
 		return nil, err
@@ -80,36 +150,362 @@ func (prd *providerResourceData) GetGitClient(tmpDir string) (*gogit.Client, err
 	if prd.git.Http != nil && prd.git.Http.InsecureHttpAllowed.ValueBool() {
 		clientOpts = append(clientOpts, gogit.WithInsecureCredentialsOverHTTP())
 	}
+	proxyURL, err := getProxyURL(prd.git.Proxy, prd.GetRepositoryURL())
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL != nil {
+		proxyOpts := transport.ProxyOptions{Username: proxyURL.User.Username()}
+		proxyOpts.Password, _ = proxyURL.User.Password()
+		proxyURL.User = nil
+		proxyOpts.URL = proxyURL.String()
+		clientOpts = append(clientOpts, gogit.WithProxy(proxyOpts))
+	}
 
 	gitClient, err := gogit.NewClient(tmpDir, authOpts, clientOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("could not create git client: %w", err)
 	}
+	sshSigner, err := prd.GetSSHSigner()
+	if err != nil {
+		return nil, err
+	}
 
+	return &signingGitClient{Client: gitClient, sshSigner: sshSigner}, nil
+}
+
+// GetSSHSigner returns the signer of commits when the signing format is ssh, it is nil otherwise.
+func (prd *providerResourceData) GetSSHSigner() (*sshsig.Signer, error) {
+	if prd.git.Signing == nil || prd.git.Signing.Format.ValueString() != signingFormatSSH {
+		return nil, nil
+	}
+	signer, err := sshsig.NewSigner([]byte(prd.git.Signing.PrivateKey.ValueString()), []byte(prd.git.Signing.Passphrase.ValueString()), sshsig.NamespaceGit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SSH signing key: %w", err)
+	}
+	return signer, nil
+}
+
+// signingGitClient signs commits with an SSH key, as the embedded client only supports OpenPGP signatures.
+// It is passed to bootstrap as well, so that all commits created by the provider are signed.
+type signingGitClient struct {
+	*gogit.Client
+	sshSigner *sshsig.Signer
+}
+
+func (c *signingGitClient) Commit(message git.Commit, opts ...repository.CommitOption) (string, error) {
+	hash, err := c.Client.Commit(message, opts...)
+	if err != nil || c.sshSigner == nil {
+		return hash, err
+	}
+	return signHeadCommit(c.Path(), c.sshSigner)
+}
+
+// signHeadCommit replaces the commit checked out in the repository with a signed copy of it.
+func signHeadCommit(repoPath string, signer extgogit.Signer) (string, error) {
+	repo, err := extgogit.PlainOpen(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("could not open git repository: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("could not resolve HEAD: %w", err)
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return "", fmt.Errorf("could not read commit %s: %w", head.Hash(), err)
+	}
+
+	unsigned := &plumbing.MemoryObject{}
+	if err := commit.EncodeWithoutSignature(unsigned); err != nil {
+		return "", err
+	}
+	r, err := unsigned.Reader()
+	if err != nil {
+		return "", err
+	}
+	signature, err := signer.Sign(r)
+	if err != nil {
+		return "", fmt.Errorf("could not sign commit: %w", err)
+	}
+	commit.PGPSignature = string(signature)
+
+	signed := repo.Storer.NewEncodedObject()
+	if err := commit.Encode(signed); err != nil {
+		return "", err
+	}
+	hash, err := repo.Storer.SetEncodedObject(signed)
+	if err != nil {
+		return "", fmt.Errorf("could not write signed commit: %w", err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(head.Name(), hash)); err != nil {
+		return "", fmt.Errorf("could not update %s: %w", head.Name(), err)
+	}
+	return hash.String(), nil
+}
+
+// CloneRepository clones the configured branch of the Git repository. Empty repositories and missing
+// branches are initialized with an initial commit, the branch is created from the base branch when set.
+func (prd *providerResourceData) CloneRepository(ctx context.Context) (*signingGitClient, error) {
+	gitClient, commit, err := prd.cloneBranch(ctx, prd.git.Branch.ValueString())
+	var notFoundErr git.ErrRepositoryNotFound
+	switch {
+	case errors.As(err, &notFoundErr):
+		if err := prd.checkRepositoryExists(ctx); err != nil {
+			return nil, err
+		}
+		return prd.initializeBranch(ctx)
+	case err != nil:
+		return nil, err
+	case commit == nil:
+		// The remote repository is empty and has been initialized locally.
+		if err := prd.pushInitialCommit(ctx, gitClient); err != nil {
+			_ = os.RemoveAll(gitClient.Path())
+			return nil, err
+		}
+	}
 	return gitClient, nil
 }
 
-func (prd *providerResourceData) CloneRepository(ctx context.Context) (*gogit.Client, error) {
+// ClonePullRequestBranch clones the head branch of an open pull request, or the configured branch
+// when the head branch is empty.
+func (prd *providerResourceData) ClonePullRequestBranch(ctx context.Context, headBranch string) (*signingGitClient, error) {
+	if headBranch == "" {
+		return prd.CloneRepository(ctx)
+	}
+	gitClient, _, err := prd.cloneBranch(ctx, headBranch)
+	if err != nil {
+		return nil, fmt.Errorf("could not clone pull request branch %s: %w", headBranch, err)
+	}
+	return gitClient, nil
+}
+
+func (prd *providerResourceData) cloneBranch(ctx context.Context, branch string) (*signingGitClient, *git.Commit, error) {
 	tmpDir, err := manifestgen.MkdirTempAbs("", "flux-bootstrap-")
 	if err != nil {
-		return nil, fmt.Errorf("could not create temporary working directory for git repository: %w", err)
+		return nil, nil, fmt.Errorf("could not create temporary working directory for git repository: %w", err)
 	}
 	gitClient, err := prd.GetGitClient(tmpDir)
 	if err != nil {
-		return nil, fmt.Errorf("could not create git client: %w", err)
+		_ = os.RemoveAll(tmpDir)
+		return nil, nil, fmt.Errorf("could not create git client: %w", err)
+	}
+	commit, err := gitClient.Clone(ctx, prd.GetRepositoryURL().String(), repository.CloneConfig{
+		CheckoutStrategy: repository.CheckoutStrategy{
+			Branch: branch,
+		},
+	})
+	if err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return nil, nil, fmt.Errorf("could not clone git repository: %w", knownHostsError(prd.git, err))
+	}
+	return gitClient, commit, nil
+}
+
+// checkRepositoryExists lists the references of the remote repository, as cloning a branch reports
+// a missing branch and a missing or inaccessible repository with the same ErrRepositoryNotFound.
+func (prd *providerResourceData) checkRepositoryExists(ctx context.Context) error {
+	tmpDir, err := manifestgen.MkdirTempAbs("", "flux-bootstrap-")
+	if err != nil {
+		return fmt.Errorf("could not create temporary working directory for git repository: %w", err)
 	}
-	// TODO: Need to conditionally clone here. If repository is empty this will fail.
-	_, err = gitClient.Clone(ctx, prd.GetRepositoryURL().String(), repository.CloneConfig{
+	defer os.RemoveAll(tmpDir)
+	gitClient, err := prd.GetGitClient(tmpDir)
+	if err != nil {
+		return fmt.Errorf("could not create git client: %w", err)
+	}
+	// Cloning a reference name resolves it with ls-remote first. Errors listing the remote are wrapped,
+	// while a reference missing from the listed remote is reported without a cause.
+	repositoryURL := prd.GetRepositoryURL().String()
+	_, err = gitClient.Clone(ctx, repositoryURL, repository.CloneConfig{
 		CheckoutStrategy: repository.CheckoutStrategy{
-			Branch: prd.git.Branch.ValueString(),
+			RefName: plumbing.NewBranchReferenceName(prd.git.Branch.ValueString()).String(),
 		},
 	})
+	if err != nil && errors.Unwrap(err) != nil {
+		return fmt.Errorf("git repository %s does not exist or is not accessible: %w", repositoryURL, knownHostsError(prd.git, err))
+	}
+	return nil
+}
+
+// initializeBranch creates the missing branch from the base branch, or as a new branch
+// with an initial commit when no base branch is configured.
+func (prd *providerResourceData) initializeBranch(ctx context.Context) (*signingGitClient, error) {
+	branch := prd.git.Branch.ValueString()
+	if baseBranch := prd.git.BaseBranch.ValueString(); baseBranch != "" {
+		gitClient, _, err := prd.cloneBranch(ctx, baseBranch)
+		if err != nil {
+			return nil, fmt.Errorf("could not clone base branch %s: %w", baseBranch, err)
+		}
+		if err := gitClient.SwitchBranch(ctx, branch); err != nil {
+			_ = os.RemoveAll(gitClient.Path())
+			return nil, fmt.Errorf("could not create branch %s from %s: %w", branch, baseBranch, err)
+		}
+		if err := gitClient.Push(ctx, repository.PushConfig{}); err != nil {
+			_ = os.RemoveAll(gitClient.Path())
+			return nil, fmt.Errorf("could not push branch %s: %w", branch, err)
+		}
+		return gitClient, nil
+	}
+
+	tmpDir, err := manifestgen.MkdirTempAbs("", "flux-bootstrap-")
+	if err != nil {
+		return nil, fmt.Errorf("could not create temporary working directory for git repository: %w", err)
+	}
+	gitClient, err := prd.GetGitClient(tmpDir)
 	if err != nil {
-		return nil, fmt.Errorf("could not clone git repository: %w", err)
+		_ = os.RemoveAll(tmpDir)
+		return nil, fmt.Errorf("could not create git client: %w", err)
+	}
+	if err := gitClient.Init(ctx, prd.GetRepositoryURL().String(), branch); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return nil, fmt.Errorf("could not initialize git repository: %w", err)
+	}
+	if err := prd.pushInitialCommit(ctx, gitClient); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return nil, err
 	}
 	return gitClient, nil
 }
 
+// pushInitialCommit commits a README file to the branch, as bootstrap requires the branch to have a commit.
+func (prd *providerResourceData) pushInitialCommit(ctx context.Context, gitClient *signingGitClient) error {
+	commit, signer, err := prd.CreateCommit("Initialize repository")
+	if err != nil {
+		return fmt.Errorf("unable to create initial commit: %w", err)
+	}
+	files := map[string]io.Reader{
+		"README.md": strings.NewReader("# Flux\n\nThis repository is managed by Flux.\n"),
+	}
+	if _, err := gitClient.Commit(commit, signer, repository.WithFiles(files)); err != nil {
+		return fmt.Errorf("unable to commit initial files: %w", err)
+	}
+	if err := gitClient.Push(ctx, repository.PushConfig{}); err != nil {
+		return fmt.Errorf("unable to push initial commit: %w", err)
+	}
+	return nil
+}
+
+// PushBranch pushes the committed changes and returns the name of the remote branch they were pushed to.
+// When pull requests are enabled a new branch is created from the changes instead of updating the
+// configured branch.
+func (prd *providerResourceData) PushBranch(ctx context.Context, gitClient *signingGitClient) (string, error) {
+	branch := prd.git.Branch.ValueString()
+	if prd.git.PullRequest == nil {
+		if err := gitClient.Push(ctx, repository.PushConfig{}); err != nil {
+			return "", err
+		}
+		return branch, nil
+	}
+
+	headBranch := prd.git.PullRequest.BranchPrefix.ValueString() + time.Now().UTC().Format("20060102150405")
+	err := gitClient.Push(ctx, repository.PushConfig{
+		Refspecs: []string{fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, headBranch)},
+	})
+	if err != nil {
+		return "", err
+	}
+	return headBranch, nil
+}
+
+// OpenPullRequest opens a pull request from the given branch against the configured branch.
+// It returns nil when the changes have been pushed to the configured branch directly.
+func (prd *providerResourceData) OpenPullRequest(ctx context.Context, headBranch, title string) (*forge.PullRequest, error) {
+	if prd.git.PullRequest == nil || headBranch == prd.git.Branch.ValueString() {
+		return nil, nil
+	}
+	f, err := prd.GetForge()
+	if err != nil {
+		return nil, err
+	}
+	description := "Changes to the Flux manifests made by the Terraform provider for Flux."
+	if prd.git.CommitMessageAppendix.ValueString() != "" {
+		description = description + "\n\n" + prd.git.CommitMessageAppendix.ValueString()
+	}
+	return f.CreatePullRequest(ctx, forge.PullRequestOptions{
+		Title:       title,
+		Description: description,
+		HeadBranch:  headBranch,
+		BaseBranch:  prd.git.Branch.ValueString(),
+	})
+}
+
+// DeleteBranch deletes the given branch from the remote repository.
+func (prd *providerResourceData) DeleteBranch(ctx context.Context, gitClient *signingGitClient, branch string) error {
+	return gitClient.Push(ctx, repository.PushConfig{
+		Refspecs: []string{fmt.Sprintf(":refs/heads/%s", branch)},
+	})
+}
+
+// GetOpenPullRequest returns the pull request with the given number when it is still open, nil when it
+// has been merged or closed, or when pull requests are not configured anymore.
+func (prd *providerResourceData) GetOpenPullRequest(ctx context.Context, number int) (*forge.PullRequest, error) {
+	if prd.git.PullRequest == nil {
+		return nil, nil
+	}
+	f, err := prd.GetForge()
+	if err != nil {
+		return nil, err
+	}
+	pr, err := f.GetPullRequest(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if pr.State != forge.StateOpen {
+		return nil, nil
+	}
+	return pr, nil
+}
+
+// WaitForPullRequest waits for the pull request to be merged when configured to.
+// The returned bool is false when the pull request is left open.
+func (prd *providerResourceData) WaitForPullRequest(ctx context.Context, pr *forge.PullRequest) (bool, error) {
+	if pr == nil {
+		return true, nil
+	}
+	if !prd.git.PullRequest.WaitForMerge.ValueBool() {
+		return false, nil
+	}
+	f, err := prd.GetForge()
+	if err != nil {
+		return false, err
+	}
+	if _, err := forge.WaitForMerge(ctx, f, pr.Number, 5*time.Second); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (prd *providerResourceData) GetForge() (forge.Forge, error) {
+	token := prd.git.PullRequest.Token.ValueString()
+	if token == "" && prd.git.GitHubApp != nil {
+		var err error
+		token, err = getGitHubAppToken(prd.git)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return forge.New(forge.Options{
+		Provider:      prd.git.PullRequest.Provider.ValueString(),
+		APIURL:        prd.git.PullRequest.APIURL.ValueString(),
+		Token:         token,
+		RepositoryURL: prd.GetRepositoryURL(),
+		HTTPClient:    getHTTPClient(prd.git.Proxy),
+	})
+}
+
+// GetRootSourceOptions returns the fields set on the root GitRepository in addition to the generated sync manifests.
+func (prd *providerResourceData) GetRootSourceOptions() rootSourceOptions {
+	opts := rootSourceOptions{}
+	if prd.git.GitHubApp != nil {
+		opts.Provider = sourcev1.GitProviderGitHub
+	}
+	if prd.git.Proxy != nil {
+		opts.ProxySecretName = prd.git.Proxy.SecretName.ValueString()
+	}
+	return opts
+}
+
 func (prd *providerResourceData) GetBootstrapProvider(tmpDir string) (*bootstrap.PlainGitBootstrapper, error) {
 	gitClient, err := prd.GetGitClient(tmpDir)
 	if err != nil {
@@ -136,7 +532,7 @@ func (prd *providerResourceData) GetBootstrapOptions() ([]bootstrap.GitOption, e
 	}
 	return []bootstrap.GitOption{
 		bootstrap.WithRepositoryURL(prd.GetRepositoryURL().String()),
-		bootstrap.WithKubeconfig(prd.rcg, &runclient.Options{}),
+		bootstrap.WithKubeconfig(prd.rcg, prd.clientOpts),
 		bootstrap.WithBranch(prd.git.Branch.ValueString()),
 		bootstrap.WithSignature(prd.git.AuthorName.ValueString(), prd.git.AuthorEmail.ValueString()),
 		bootstrap.WithCommitMessageAppendix(prd.git.CommitMessageAppendix.ValueString()),
@@ -166,11 +562,111 @@ func (prd *providerResourceData) GetSecretOptions(secretName, namespace, targetP
 			secretOpts.Keypair = keypair
 			secretOpts.Password = prd.git.Ssh.Password.ValueString()
 		}
-		secretOpts.SSHHostname = prd.git.Url.ValueURL().Host
+		// The host key is only scanned when known_hosts is not pinned, see ReconcileSyncSecret.
+		if prd.git.Ssh.KnownHosts.ValueString() == "" {
+			secretOpts.SSHHostname = prd.git.Url.ValueURL().Host
+		}
 	}
 	return secretOpts, nil
 }
 
+// ReconcileSyncSecret writes the sync secret with the configured known_hosts, bearer token or GitHub App
+// credentials to the cluster. The returned options only reference the secret so that bootstrap keeps it as
+// is instead of generating a new one. It is a no-op when none of them is configured.
+func (prd *providerResourceData) ReconcileSyncSecret(ctx context.Context, secretOpts sourcesecret.Options) (sourcesecret.Options, error) {
+	hasKnownHosts := prd.git.Ssh != nil && prd.git.Ssh.KnownHosts.ValueString() != ""
+	hasBearerToken := prd.git.Http != nil && prd.git.Http.BearerToken.ValueString() != ""
+	if !hasKnownHosts && !hasBearerToken && prd.git.GitHubApp == nil {
+		return secretOpts, nil
+	}
+
+	manifest, err := sourcesecret.Generate(secretOpts)
+	if err != nil {
+		return sourcesecret.Options{}, fmt.Errorf("could not generate sync secret: %w", err)
+	}
+	var secret corev1.Secret
+	if err := yaml.Unmarshal([]byte(manifest.Content), &secret); err != nil {
+		return sourcesecret.Options{}, fmt.Errorf("could not read sync secret: %w", err)
+	}
+	if secret.StringData == nil {
+		secret.StringData = map[string]string{}
+	}
+	if hasKnownHosts {
+		secret.StringData[sourcesecret.KnownHostsSecretKey] = prd.git.Ssh.KnownHosts.ValueString()
+	}
+	if hasBearerToken {
+		secret.StringData[bearerTokenSecretKey] = prd.git.Http.BearerToken.ValueString()
+	}
+	if app := prd.git.GitHubApp; app != nil {
+		secret.StringData[gitHubAppIDSecretKey] = app.AppID.ValueString()
+		secret.StringData[gitHubAppInstallationIDSecretKey] = app.InstallationID.ValueString()
+		secret.StringData[gitHubAppPrivateKeySecretKey] = app.PrivateKey.ValueString()
+		if app.BaseURL.ValueString() != "" {
+			secret.StringData[gitHubAppBaseURLSecretKey] = app.BaseURL.ValueString()
+		}
+	}
+
+	if err := prd.applySecret(ctx, &secret); err != nil {
+		return sourcesecret.Options{}, err
+	}
+	return sourcesecret.Options{
+		Name:      secretOpts.Name,
+		Namespace: secretOpts.Namespace,
+	}, nil
+}
+
+// ReconcileProxySecret writes the proxy address and credentials to the secret referenced by
+// the root GitRepository, it does nothing unless proxy.secret_name is set.
+func (prd *providerResourceData) ReconcileProxySecret(ctx context.Context, namespace string) error {
+	if prd.git.Proxy == nil || prd.git.Proxy.SecretName.ValueString() == "" {
+		return nil
+	}
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      prd.git.Proxy.SecretName.ValueString(),
+			Namespace: namespace,
+		},
+		StringData: map[string]string{
+			proxyAddressSecretKey: prd.git.Proxy.Url.ValueString(),
+		},
+	}
+	if prd.git.Proxy.Username.ValueString() != "" {
+		secret.StringData[proxyUsernameSecretKey] = prd.git.Proxy.Username.ValueString()
+		secret.StringData[proxyPasswordSecretKey] = prd.git.Proxy.Password.ValueString()
+	}
+	return prd.applySecret(ctx, &secret)
+}
+
+// applySecret creates the secret or replaces the data of the existing one.
+func (prd *providerResourceData) applySecret(ctx context.Context, secret *corev1.Secret) error {
+	kubeClient, err := prd.GetKubernetesClient()
+	if err != nil {
+		return err
+	}
+	// The namespace is normally created by bootstrap, which runs after the secret is written.
+	namespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: secret.Namespace}}
+	if err := kubeClient.Create(ctx, &namespace); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("could not create namespace %s: %w", namespace.Name, err)
+	}
+	existing := corev1.Secret{}
+	err = kubeClient.Get(ctx, client.ObjectKeyFromObject(secret), &existing)
+	switch {
+	case k8serrors.IsNotFound(err):
+		if err := kubeClient.Create(ctx, secret); err != nil {
+			return fmt.Errorf("could not create secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+	case err != nil:
+		return fmt.Errorf("could not get secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	default:
+		existing.Data = nil
+		existing.StringData = secret.StringData
+		if err := kubeClient.Update(ctx, &existing); err != nil {
+			return fmt.Errorf("could not update secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+	}
+	return nil
+}
+
 func (prd *providerResourceData) CreateCommit(message string) (git.Commit, repository.CommitOption, error) {
 	entityList, err := prd.GetEntityList()
 	if err != nil {
@@ -219,6 +715,33 @@ func (prd *providerResourceData) GetEntityList() (openpgp.EntityList, error) {
 			return nil, fmt.Errorf("failed to read GPG key ring: %w", err)
 		}
 	}
+	if prd.git.GpgKeyRingContent.ValueString() != "" {
+		var err error
+		entityList, err = readGpgKeyRing(prd.git.GpgKeyRingContent.ValueString())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entityList, nil
+}
+
+// readGpgKeyRing parses an ASCII armored or base64 encoded binary key ring.
+func readGpgKeyRing(content string) (openpgp.EntityList, error) {
+	if strings.HasPrefix(strings.TrimSpace(content), "-----BEGIN PGP") {
+		entityList, err := openpgp.ReadArmoredKeyRing(strings.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read armored GPG key ring: %w", err)
+		}
+		return entityList, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode GPG key ring, expected ASCII armor or base64: %w", err)
+	}
+	entityList, err := openpgp.ReadKeyRing(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GPG key ring: %w", err)
+	}
 	return entityList, nil
 }
 
@@ -249,6 +772,9 @@ func getOpenPgpEntity(keyRing openpgp.EntityList, passphrase, keyID string) (*op
 		}
 	} else {
 		entity = keyRing[0]
+		if entity.PrivateKey == nil {
+			return nil, fmt.Errorf("keyring does not contain private key for key id '%s'", entity.PrimaryKey.KeyIdString())
+		}
 	}
 
 	err := entity.PrivateKey.Decrypt([]byte(passphrase))
@@ -267,28 +793,45 @@ func getAuthOpts(g *Git) (*git.AuthOptions, error) {
 			return nil, fmt.Errorf("git URL scheme is http but http configuration is empty")
 		}
 		return &git.AuthOptions{
-			Transport: git.HTTP,
-			Username:  g.Http.Username.ValueString(),
-			Password:  g.Http.Password.ValueString(),
+			Transport:   git.HTTP,
+			Username:    g.Http.Username.ValueString(),
+			Password:    g.Http.Password.ValueString(),
+			BearerToken: g.Http.BearerToken.ValueString(),
 		}, nil
 	case "https":
-		if g.Http == nil {
+		if g.Http == nil && g.GitHubApp == nil {
 			return nil, fmt.Errorf("git URL scheme is https but http configuration is empty")
 		}
-		return &git.AuthOptions{
+		authOpts := &git.AuthOptions{
 			Transport: git.HTTPS,
-			Username:  g.Http.Username.ValueString(),
-			Password:  g.Http.Password.ValueString(),
-			CAFile:    []byte(g.Http.CertificateAuthority.ValueString()),
-		}, nil
+		}
+		if g.Http != nil {
+			authOpts.Username = g.Http.Username.ValueString()
+			authOpts.Password = g.Http.Password.ValueString()
+			authOpts.BearerToken = g.Http.BearerToken.ValueString()
+			authOpts.CAFile = []byte(g.Http.CertificateAuthority.ValueString())
+		}
+		if g.GitHubApp != nil {
+			token, err := getGitHubAppToken(g)
+			if err != nil {
+				return nil, err
+			}
+			authOpts.Username = gitHubAppUsername
+			authOpts.Password = token
+		}
+		return authOpts, nil
 	case "ssh":
 		if g.Ssh == nil {
 			return nil, fmt.Errorf("git URL scheme is ssh but ssh configuration is empty")
 		}
 		if g.Ssh.PrivateKey.ValueString() != "" {
-			kh, err := sourcesecret.ScanHostKey(u.Host)
-			if err != nil {
-				return nil, err
+			kh := []byte(g.Ssh.KnownHosts.ValueString())
+			if len(kh) == 0 {
+				var err error
+				kh, err = sourcesecret.ScanHostKey(u.Host)
+				if err != nil {
+					return nil, err
+				}
 			}
 			return &git.AuthOptions{
 				Transport:  git.SSH,
@@ -304,6 +847,73 @@ func getAuthOpts(g *Git) (*git.AuthOptions, error) {
 	}
 }
 
+// getGitHubAppToken mints an installation token, which is valid for an hour.
+func getGitHubAppToken(g *Git) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return forge.GitHubAppToken(ctx, forge.GitHubAppOptions{
+		AppID:          g.GitHubApp.AppID.ValueString(),
+		InstallationID: g.GitHubApp.InstallationID.ValueString(),
+		PrivateKey:     []byte(g.GitHubApp.PrivateKey.ValueString()),
+		BaseURL:        g.GitHubApp.BaseURL.ValueString(),
+		HTTPClient:     getHTTPClient(g.Proxy),
+	})
+}
+
+// getProxyURL returns the proxy, including its credentials, used to reach the target. It is nil when no
+// proxy is configured or the target matches no_proxy, which follows the semantics of the NO_PROXY variable.
+func getProxyURL(p *Proxy, target *url.URL) (*url.URL, error) {
+	if p == nil || target == nil {
+		return nil, nil
+	}
+	proxyURL := *p.Url.ValueURL()
+	if p.Username.ValueString() != "" {
+		proxyURL.User = url.UserPassword(p.Username.ValueString(), p.Password.ValueString())
+	}
+	noProxy := []string{}
+	for _, v := range p.NoProxy.Elements() {
+		if s, ok := v.(types.String); ok {
+			noProxy = append(noProxy, s.ValueString())
+		}
+	}
+	cfg := httpproxy.Config{
+		HTTPProxy:  proxyURL.String(),
+		HTTPSProxy: proxyURL.String(),
+		NoProxy:    strings.Join(noProxy, ","),
+	}
+	// Only http(s) requests are matched, SSH remotes are matched as https.
+	u := *target
+	if u.Scheme != httpScheme {
+		u.Scheme = "https"
+	}
+	return cfg.ProxyFunc()(&u)
+}
+
+// getHTTPClient returns the client used for API calls to the Git hosting service, it is nil without proxy.
+func getHTTPClient(p *Proxy) *http.Client {
+	if p == nil {
+		return nil
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = func(req *http.Request) (*url.URL, error) {
+		return getProxyURL(p, req.URL)
+	}
+	return &http.Client{Transport: t}
+}
+
+// knownHostsError makes host key verification failures explicit when known_hosts is pinned.
+func knownHostsError(g *Git, err error) error {
+	var keyErr *knownhosts.KeyError
+	if g.Ssh == nil || g.Ssh.KnownHosts.ValueString() == "" || !errors.As(err, &keyErr) {
+		return err
+	}
+	// The host is unknown when no key is expected for it.
+	if len(keyErr.Want) == 0 {
+		return fmt.Errorf("the host %s is not in the configured known_hosts: %w", g.Url.ValueURL().Host, err)
+	}
+	return fmt.Errorf("the host key of %s does not match the configured known_hosts: %w", g.Url.ValueURL().Host, err)
+}
+
 func getClientConfiguration(ctx context.Context, kubernetes *Kubernetes) (clientcmd.ClientConfig, error) {
 	overrides := &clientcmd.ConfigOverrides{}
 	loader := &clientcmd.ClientConfigLoadingRules{}
@@ -375,6 +985,21 @@ func getClientConfiguration(ctx context.Context, kubernetes *Kubernetes) (client
 		overrides.AuthInfo.ClientKeyData = bytes.NewBufferString(kubernetes.ClientKey.ValueString()).Bytes()
 	}
 	overrides.ClusterDefaults.ProxyURL = kubernetes.ProxyURL.ValueString()
+	overrides.ClusterInfo.TLSServerName = kubernetes.TLSServerName.ValueString()
+	if !kubernetes.RequestTimeout.IsNull() {
+		overrides.Timeout = kubernetes.RequestTimeout.ValueDuration().String()
+	}
+
+	// Impersonation of the kubeconfig is replaced only when configured.
+	if kubernetes.As.ValueString() != "" {
+		overrides.AuthInfo.Impersonate = kubernetes.As.ValueString()
+		overrides.AuthInfo.ImpersonateUID = kubernetes.AsUID.ValueString()
+		var groups []string
+		if diag := kubernetes.AsGroups.ElementsAs(ctx, &groups, false); diag.HasError() {
+			return nil, fmt.Errorf("%s", diag)
+		}
+		overrides.AuthInfo.ImpersonateGroups = groups
+	}
 
 	if kubernetes.Exec != nil {
 		var args []string
@@ -403,6 +1028,33 @@ func getClientConfiguration(ctx context.Context, kubernetes *Kubernetes) (client
 		overrides.AuthInfo.Exec = exec
 	}
 
+	if kubernetes.InCluster.ValueBool() {
+		cfg, err := restclient.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load in-cluster configuration: %w", err)
+		}
+		return clientcmd.NewNonInteractiveClientConfig(inClusterKubeconfig(cfg), inClusterContext, overrides, nil), nil
+	}
+
 	cc := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
 	return cc, nil
 }
+
+// inClusterKubeconfig returns a kubeconfig for the in-cluster configuration, which allows the overrides
+// to be applied to it. The token is read from its file so that rotated tokens are picked up.
+func inClusterKubeconfig(cfg *restclient.Config) clientcmdapi.Config {
+	config := clientcmdapi.NewConfig()
+	cluster := clientcmdapi.NewCluster()
+	cluster.Server = cfg.Host
+	cluster.CertificateAuthority = cfg.TLSClientConfig.CAFile
+	config.Clusters[inClusterContext] = cluster
+	authInfo := clientcmdapi.NewAuthInfo()
+	authInfo.TokenFile = cfg.BearerTokenFile
+	config.AuthInfos[inClusterContext] = authInfo
+	kubeContext := clientcmdapi.NewContext()
+	kubeContext.Cluster = inClusterContext
+	kubeContext.AuthInfo = inClusterContext
+	config.Contexts[inClusterContext] = kubeContext
+	config.CurrentContext = inClusterContext
+	return *config
+}
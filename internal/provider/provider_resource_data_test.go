@@ -0,0 +1,307 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh/knownhosts"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	customtypes "github.com/fluxcd/terraform-provider-flux/internal/framework/types"
+)
+
+func TestKnownHostsError(t *testing.T) {
+	u, err := url.Parse("ssh://git@github.com/fluxcd/fleet.git")
+	require.NoError(t, err)
+	g := &Git{
+		Url: customtypes.URLValue(u),
+		Ssh: &Ssh{KnownHosts: types.StringValue("github.com ssh-ed25519 AAAA")},
+	}
+
+	mismatch := fmt.Errorf("unable to clone: ssh: handshake failed: %w", &knownhosts.KeyError{Want: []knownhosts.KnownKey{{Line: 1}}})
+	err = knownHostsError(g, mismatch)
+	require.ErrorContains(t, err, "the host key of github.com does not match the configured known_hosts")
+	require.ErrorIs(t, err, mismatch)
+
+	unknown := fmt.Errorf("unable to clone: ssh: handshake failed: %w", &knownhosts.KeyError{})
+	require.ErrorContains(t, knownHostsError(g, unknown), "the host github.com is not in the configured known_hosts")
+
+	other := errors.New("knownhosts: key mismatch in the error message only")
+	require.Equal(t, other, knownHostsError(g, other))
+
+	require.Equal(t, mismatch, knownHostsError(&Git{Url: customtypes.URLValue(u)}, mismatch))
+}
+
+func TestCloneRepositoryThroughProxy(t *testing.T) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git is not installed")
+	}
+	root := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--initial-branch=main", filepath.Join(root, "fleet")},
+		{"-C", filepath.Join(root, "fleet"), "-c", "user.name=flux", "-c", "user.email=flux@example.com", "commit", "--allow-empty", "-m", "init"},
+	} {
+		out, err := exec.Command(gitPath, args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	// The proxy serves the repository itself, as the host of the repository URL does not resolve.
+	backend := &cgi.Handler{
+		Path: gitPath,
+		Args: []string{"http-backend"},
+		Env:  []string{"GIT_PROJECT_ROOT=" + root, "GIT_HTTP_EXPORT_ALL=1"},
+	}
+	var mu sync.Mutex
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "git.example.test" {
+			http.Error(w, "unexpected host", http.StatusBadGateway)
+			return
+		}
+		mu.Lock()
+		proxied = append(proxied, r.URL.Path)
+		mu.Unlock()
+		backend.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	repositoryURL, err := url.Parse("http://git.example.test/fleet")
+	require.NoError(t, err)
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+	prd := &providerResourceData{git: &Git{
+		Url:    customtypes.URLValue(repositoryURL),
+		Branch: types.StringValue("main"),
+		Http:   &Http{InsecureHttpAllowed: types.BoolValue(true)},
+		Proxy:  &Proxy{Url: customtypes.URLValue(proxyURL), NoProxy: types.ListNull(types.StringType)},
+	}}
+	gitClient, err := prd.CloneRepository(context.Background())
+	require.NoError(t, err)
+	defer os.RemoveAll(gitClient.Path())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Contains(t, proxied, "/fleet/info/refs")
+}
+
+func TestValidateGitProxy(t *testing.T) {
+	sshURL, err := url.Parse("ssh://git@github.com/fluxcd/fleet.git")
+	require.NoError(t, err)
+	httpProxy, err := url.Parse("http://proxy.example.com:3128")
+	require.NoError(t, err)
+	socksProxy, err := url.Parse("socks5://proxy.example.com:1080")
+	require.NoError(t, err)
+
+	diags := validateGit(&Git{
+		Url:   customtypes.URLValue(sshURL),
+		Proxy: &Proxy{Url: customtypes.URLValue(httpProxy), NoProxy: types.ListNull(types.StringType)},
+	})
+	require.True(t, diags.HasError())
+	require.Contains(t, diags.Errors()[0].Detail(), "socks5")
+
+	diags = validateGit(&Git{
+		Url:   customtypes.URLValue(sshURL),
+		Proxy: &Proxy{Url: customtypes.URLValue(socksProxy), NoProxy: types.ListNull(types.StringType)},
+	})
+	require.False(t, diags.HasError())
+
+	diags = validateGit(&Git{
+		Url:   customtypes.URLValue(sshURL),
+		Proxy: &Proxy{Url: customtypes.URLValue(httpProxy), NoProxy: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("github.com")})},
+	})
+	require.False(t, diags.HasError())
+}
+
+func TestWithOverridesCopiesConfiguration(t *testing.T) {
+	u, err := url.Parse("https://github.com/fluxcd/fleet.git")
+	require.NoError(t, err)
+	prd := &providerResourceData{git: &Git{
+		Url:         customtypes.URLValue(u),
+		Http:        &Http{Password: types.StringValue("token")},
+		PullRequest: &PullRequest{},
+	}}
+	kubernetes := &Kubernetes{ConfigPath: types.StringValue("kubeconfig")}
+
+	overridden, err := prd.WithOverrides(context.Background(), nil, kubernetes)
+	require.NoError(t, err)
+	require.Equal(t, "main", overridden.git.Branch.ValueString())
+	require.Equal(t, "token", overridden.git.PullRequest.Token.ValueString())
+	require.False(t, overridden.kubernetes.InCluster.IsNull())
+
+	// Neither the provider nor the resource configuration is changed by the defaults.
+	require.True(t, prd.git.Branch.IsNull())
+	require.True(t, prd.git.PullRequest.Token.IsNull())
+	require.True(t, kubernetes.InCluster.IsNull())
+}
+
+func TestGetClientConfiguration(t *testing.T) {
+	ctx := context.Background()
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+users:
+- name: test
+  user:
+    token: secret
+    as: kubeconfig-user
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+`), 0o600))
+
+	tests := []struct {
+		name       string
+		kubernetes *Kubernetes
+		assert     func(t *testing.T, cfg *restclient.Config)
+	}{
+		{
+			name:       "kubeconfig",
+			kubernetes: &Kubernetes{ConfigPath: types.StringValue(kubeconfig)},
+			assert: func(t *testing.T, cfg *restclient.Config) {
+				require.Equal(t, "https://127.0.0.1:6443", cfg.Host)
+				require.Equal(t, "kubeconfig-user", cfg.Impersonate.UserName)
+				require.Empty(t, cfg.TLSClientConfig.ServerName)
+				require.Zero(t, cfg.QPS)
+				require.Zero(t, cfg.Burst)
+				require.Zero(t, cfg.Timeout)
+			},
+		},
+		{
+			name: "impersonation",
+			kubernetes: &Kubernetes{
+				ConfigPath: types.StringValue(kubeconfig),
+				As:         types.StringValue("flux"),
+				AsGroups:   types.ListValueMust(types.StringType, []attr.Value{types.StringValue("system:masters")}),
+				AsUID:      types.StringValue("1000"),
+			},
+			assert: func(t *testing.T, cfg *restclient.Config) {
+				require.Equal(t, "flux", cfg.Impersonate.UserName)
+				require.Equal(t, "1000", cfg.Impersonate.UID)
+				require.Equal(t, []string{"system:masters"}, cfg.Impersonate.Groups)
+			},
+		},
+		{
+			name: "tls server name",
+			kubernetes: &Kubernetes{
+				ConfigPath:    types.StringValue(kubeconfig),
+				TLSServerName: types.StringValue("kubernetes.default.svc"),
+			},
+			assert: func(t *testing.T, cfg *restclient.Config) {
+				require.Equal(t, "kubernetes.default.svc", cfg.TLSClientConfig.ServerName)
+			},
+		},
+		{
+			name: "client options",
+			kubernetes: &Kubernetes{
+				ConfigPath:     types.StringValue(kubeconfig),
+				QPS:            types.Float64Value(50),
+				Burst:          types.Int64Value(100),
+				RequestTimeout: customtypes.DurationValue(time.Minute),
+			},
+			assert: func(t *testing.T, cfg *restclient.Config) {
+				require.Equal(t, float32(50), cfg.QPS)
+				require.Equal(t, 100, cfg.Burst)
+				require.Equal(t, time.Minute, cfg.Timeout)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prd, err := NewProviderResourceData(ctx, ProviderModel{Kubernetes: tt.kubernetes})
+			require.NoError(t, err)
+			cfg, err := prd.rcg.ToRESTConfig()
+			require.NoError(t, err)
+			tt.assert(t, cfg)
+		})
+	}
+}
+
+func TestInClusterKubeconfig(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret"), 0o600))
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, []byte{}, 0o600))
+
+	// The in-cluster configuration requires the service account of a pod.
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	_, err := getClientConfiguration(context.Background(), &Kubernetes{InCluster: types.BoolValue(true)})
+	require.ErrorContains(t, err, "failed to load in-cluster configuration")
+
+	inCluster := &restclient.Config{
+		Host:            "https://10.96.0.1:443",
+		BearerTokenFile: tokenFile,
+		TLSClientConfig: restclient.TLSClientConfig{CAFile: caFile},
+	}
+	overrides := &clientcmd.ConfigOverrides{}
+	overrides.AuthInfo.Impersonate = "flux"
+	overrides.ClusterInfo.TLSServerName = "kubernetes.default.svc"
+	cfg, err := clientcmd.NewNonInteractiveClientConfig(inClusterKubeconfig(inCluster), inClusterContext, overrides, nil).ClientConfig()
+	require.NoError(t, err)
+	require.Equal(t, "https://10.96.0.1:443", cfg.Host)
+	require.Equal(t, tokenFile, cfg.BearerTokenFile)
+	require.Equal(t, caFile, cfg.TLSClientConfig.CAFile)
+	require.Equal(t, "flux", cfg.Impersonate.UserName)
+	require.Equal(t, "kubernetes.default.svc", cfg.TLSClientConfig.ServerName)
+}
+
+func TestInClusterDefault(t *testing.T) {
+	for _, env := range []string{"KUBERNETES_SERVICE_HOST", "KUBE_CONFIG_PATH", "KUBE_CONFIG_PATHS"} {
+		t.Setenv(env, "")
+		require.NoError(t, os.Unsetenv(env))
+	}
+	data := ProviderModel{}
+	setProviderDefaults(context.Background(), &data)
+	require.Nil(t, data.Kubernetes)
+
+	// Running in a pod without a kubernetes block uses the in-cluster configuration.
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.96.0.1")
+	setProviderDefaults(context.Background(), &data)
+	require.NotNil(t, data.Kubernetes)
+	require.True(t, data.Kubernetes.InCluster.ValueBool())
+
+	data = ProviderModel{}
+	t.Setenv("KUBE_CONFIG_PATH", "kubeconfig")
+	setProviderDefaults(context.Background(), &data)
+	require.Equal(t, "kubeconfig", data.Kubernetes.ConfigPath.ValueString())
+	require.False(t, data.Kubernetes.InCluster.ValueBool())
+}
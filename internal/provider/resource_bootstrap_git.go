@@ -22,11 +22,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
-	"sort"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -35,19 +36,21 @@ import (
 	"github.com/fluxcd/pkg/apis/meta"
 	"github.com/fluxcd/pkg/runtime/conditions"
 	"github.com/google/go-containerregistry/pkg/name"
-	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -55,17 +58,20 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	apitypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/kustomize/api/konfig"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	"sigs.k8s.io/yaml"
 
 	"github.com/fluxcd/flux2/v2/pkg/bootstrap"
-	"github.com/fluxcd/flux2/v2/pkg/log"
 	"github.com/fluxcd/flux2/v2/pkg/manifestgen/install"
 	"github.com/fluxcd/flux2/v2/pkg/manifestgen/sourcesecret"
 	"github.com/fluxcd/flux2/v2/pkg/manifestgen/sync"
-	"github.com/fluxcd/flux2/v2/pkg/uninstall"
 	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
 	"github.com/fluxcd/pkg/git"
 	"github.com/fluxcd/pkg/git/repository"
@@ -73,6 +79,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 
+	"github.com/fluxcd/terraform-provider-flux/internal/forge"
 	customtypes "github.com/fluxcd/terraform-provider-flux/internal/framework/types"
 	"github.com/fluxcd/terraform-provider-flux/internal/framework/validators"
 	"github.com/fluxcd/terraform-provider-flux/internal/utils"
@@ -92,36 +99,84 @@ const (
 
 	missingConfiguration                   = "Missing configuration"
 	bootstrapGitResourceMissingConfigError = "Git and Kubernetes configuration not found"
+	readRepositoryConfigurationError       = "Could not read Flux configuration from Git repository"
+	bootstrapGitResourceImportConfigError  = "Importing requires the git and kubernetes configuration of the provider, the git and kubernetes attributes of the resource are not available during import."
 )
 
 type bootstrapGitResourceData struct {
-	ClusterDomain         types.String         `tfsdk:"cluster_domain"`
-	Components            types.Set            `tfsdk:"components"`
-	ComponentsExtra       types.Set            `tfsdk:"components_extra"`
+	installOptionsData
 	DeleteGitManifests    types.Bool           `tfsdk:"delete_git_manifests"`
+	DeletionPolicy        types.String         `tfsdk:"deletion_policy"`
 	DisableSecretCreation types.Bool           `tfsdk:"disable_secret_creation"`
-	EmbeddedManifests     types.Bool           `tfsdk:"embedded_manifests"`
+	Drift                 types.List           `tfsdk:"drift"`
 	ID                    types.String         `tfsdk:"id"`
-	ImagePullSecret       types.String         `tfsdk:"image_pull_secret"`
+	Images                types.List           `tfsdk:"images"`
 	Interval              customtypes.Duration `tfsdk:"interval"`
 	KeepNamespace         types.Bool           `tfsdk:"keep_namespace"`
-	KustomizationOverride types.String         `tfsdk:"kustomization_override"`
-	LogLevel              types.String         `tfsdk:"log_level"`
+	KustomizationOverride customtypes.YAML     `tfsdk:"kustomization_override"`
 	ManifestsPath         types.String         `tfsdk:"manifests_path"`
-	Namespace             types.String         `tfsdk:"namespace"`
-	NetworkPolicy         types.Bool           `tfsdk:"network_policy"`
+	OpenPullRequest       types.Object         `tfsdk:"open_pull_request"`
 	Path                  types.String         `tfsdk:"path"`
+	Patches               types.List           `tfsdk:"patches"`
 	RecurseSubmodules     types.Bool           `tfsdk:"recurse_submodules"`
-	Registry              customtypes.URL      `tfsdk:"registry"`
-	RegistryCredentials   types.String         `tfsdk:"registry_credentials"`
 	RepositoryFiles       types.Map            `tfsdk:"repository_files"`
 	SecretName            types.String         `tfsdk:"secret_name"`
 	Timeouts              timeouts.Value       `tfsdk:"timeouts"`
-	TolerationKeys        types.Set            `tfsdk:"toleration_keys"`
-	Version               types.String         `tfsdk:"version"`
-	WatchAllNamespaces    types.Bool           `tfsdk:"watch_all_namespaces"`
+	Git                   *Git                 `tfsdk:"git"`
+	Kubernetes            *Kubernetes          `tfsdk:"kubernetes"`
 }
 
+// kustomizationPatch is a patch of the Flux manifests written to the kustomization.yaml.
+type kustomizationPatch struct {
+	Patch  customtypes.YAML `tfsdk:"patch"`
+	Target *patchTarget     `tfsdk:"target"`
+}
+
+type patchTarget struct {
+	Group              types.String `tfsdk:"group"`
+	Version            types.String `tfsdk:"version"`
+	Kind               types.String `tfsdk:"kind"`
+	Name               types.String `tfsdk:"name"`
+	Namespace          types.String `tfsdk:"namespace"`
+	LabelSelector      types.String `tfsdk:"label_selector"`
+	AnnotationSelector types.String `tfsdk:"annotation_selector"`
+}
+
+// kustomizationImage is an image override of the Flux manifests written to the kustomization.yaml.
+type kustomizationImage struct {
+	Name    types.String `tfsdk:"name"`
+	NewName types.String `tfsdk:"new_name"`
+	NewTag  types.String `tfsdk:"new_tag"`
+	Digest  types.String `tfsdk:"digest"`
+}
+
+var (
+	patchTargetType = types.ObjectType{AttrTypes: map[string]attr.Type{
+		"group":               types.StringType,
+		"version":             types.StringType,
+		"kind":                types.StringType,
+		"name":                types.StringType,
+		"namespace":           types.StringType,
+		"label_selector":      types.StringType,
+		"annotation_selector": types.StringType,
+	}}
+	kustomizationPatchType = types.ObjectType{AttrTypes: map[string]attr.Type{
+		"patch":  customtypes.YAMLType{},
+		"target": patchTargetType,
+	}}
+	kustomizationImageType = types.ObjectType{AttrTypes: map[string]attr.Type{
+		"name":     types.StringType,
+		"new_name": types.StringType,
+		"new_tag":  types.StringType,
+		"digest":   types.StringType,
+	}}
+	openPullRequestType = types.ObjectType{AttrTypes: map[string]attr.Type{
+		"branch": types.StringType,
+		"number": types.Int64Type,
+		"url":    types.StringType,
+	}}
+)
+
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &bootstrapGitResource{}
 var _ resource.ResourceWithConfigure = &bootstrapGitResource{}
@@ -157,188 +212,338 @@ func (r *bootstrapGitResource) Metadata(ctx context.Context, req resource.Metada
 
 func (r *bootstrapGitResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	defaultOpts := install.MakeDefaultOptions()
-	componentsSet, diags := types.SetValueFrom(ctx, types.StringType, defaultOpts.Components)
+	attributes, diags := installSchemaAttributes(ctx)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	resp.Schema = schema.Schema{
-		MarkdownDescription: "Commits Flux components to a Git repository and configures a Kubernetes cluster to synchronize with the same Git repository.",
-		Attributes: map[string]schema.Attribute{
-			"cluster_domain": schema.StringAttribute{
-				Description: fmt.Sprintf("The internal cluster domain. Defaults to `%s`", defaultOpts.ClusterDomain),
-				Optional:    true,
-				Computed:    true,
-				Default:     stringdefault.StaticString(defaultOpts.ClusterDomain),
-			},
-			"components": schema.SetAttribute{
-				ElementType: types.StringType,
-				Description: fmt.Sprintf("Toolkit components to include in the install manifests. Defaults to `%s`", defaultOpts.Components),
-				Optional:    true,
-				Computed:    true,
-				Default:     setdefault.StaticValue(componentsSet),
-				Validators: []validator.Set{
-					setvalidator.SizeAtLeast(2),
-					setvalidator.ValueStringsAre(stringvalidator.OneOf("source-controller", "kustomize-controller", "helm-controller", "notification-controller")),
-					validators.MustContain("source-controller", "kustomize-controller"),
-				},
-			},
-			"components_extra": schema.SetAttribute{
-				ElementType: types.StringType,
-				Description: "List of extra components to include in the install manifests.",
-				Optional:    true,
-				Validators: []validator.Set{
-					setvalidator.SizeAtMost(3),
-					setvalidator.ValueStringsAre(stringvalidator.OneOf("image-reflector-controller", "image-automation-controller", "source-watcher")),
-				},
-			},
-			"delete_git_manifests": schema.BoolAttribute{
-				Description: "Delete manifests from git repository. Defaults to `true`.",
-				Optional:    true,
-				Computed:    true,
-				Default:     booldefault.StaticBool(true),
-			},
-			"disable_secret_creation": schema.BoolAttribute{
-				Description: "Use the existing secret for flux controller and don't create one from bootstrap",
-				Optional:    true,
-			},
-			"embedded_manifests": schema.BoolAttribute{
-				Description: "When enabled, the Flux manifests will be extracted from the provider binary instead of being downloaded from GitHub.com. Defaults to `false`.",
-				Optional:    true,
-				Computed:    true,
-				Default:     booldefault.StaticBool(false),
-			},
-			"id": schema.StringAttribute{
-				Computed: true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.UseStateForUnknown(),
-				},
-			},
-			"image_pull_secret": schema.StringAttribute{
-				Description: "Kubernetes secret name used for pulling the toolkit images from a private registry.",
-				Optional:    true,
-				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(rfc1123DomainRegex), rfc1123DomainError),
-					stringvalidator.LengthAtMost(253),
-				},
-			},
-			"interval": schema.StringAttribute{
-				CustomType:  customtypes.DurationType{},
-				Description: fmt.Sprintf("Interval at which to reconcile from bootstrap repository. Defaults to `%s`.", time.Minute.String()),
-				Optional:    true,
-				Computed:    true,
-				Default:     stringdefault.StaticString(time.Minute.String()),
-			},
-			"keep_namespace": schema.BoolAttribute{
-				Description: "Keep the namespace after uninstalling Flux components. Defaults to `false`.",
-				Optional:    true,
-				Computed:    true,
-				Default:     booldefault.StaticBool(false),
+	maps.Copy(attributes, map[string]schema.Attribute{
+		"delete_git_manifests": schema.BoolAttribute{
+			Description: "Delete manifests from git repository. Defaults to `true`.",
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(true),
+		},
+		"deletion_policy": schema.StringAttribute{
+			Description: fmt.Sprintf("What happens to Flux in the cluster when the resource is destroyed. `%s` removes the Flux components, CRDs and custom resources, `%s` only removes the resource from the state and leaves the cluster and the Git repository untouched, `%s` suspends the root Kustomization and %s and keeps the controllers running, `%s` removes the Flux components but keeps the CRDs, custom resources and namespace. Defaults to `%s`.", deletionPolicyUninstall, deletionPolicyOrphan, deletionPolicySuspend, sourcev1.GitRepositoryKind, deletionPolicyKeepCRDs, deletionPolicyUninstall),
+			Optional:    true,
+			Computed:    true,
+			Default:     stringdefault.StaticString(deletionPolicyUninstall),
+			Validators: []validator.String{
+				stringvalidator.OneOf(deletionPolicies...),
 			},
-			"kustomization_override": schema.StringAttribute{
-				Description: "Kustomization to override configuration set by default.",
-				Optional:    true,
-				Validators:  []validator.String{validators.KustomizationOverride()},
+		},
+		"disable_secret_creation": schema.BoolAttribute{
+			Description: "Use the existing secret for flux controller and don't create one from bootstrap",
+			Optional:    true,
+		},
+		"id": schema.StringAttribute{
+			Computed: true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
 			},
-			"log_level": schema.StringAttribute{
-				Description: fmt.Sprintf("Log level for toolkit components. Defaults to `%s`.", defaultOpts.LogLevel),
-				Optional:    true,
-				Computed:    true,
-				Default:     stringdefault.StaticString(defaultOpts.LogLevel),
-				Validators: []validator.String{
-					stringvalidator.OneOf("info", "debug", "error"),
+		},
+		"interval": schema.StringAttribute{
+			CustomType:  customtypes.DurationType{},
+			Description: fmt.Sprintf("Interval at which to reconcile from bootstrap repository. Defaults to `%s`.", time.Minute.String()),
+			Optional:    true,
+			Computed:    true,
+			Default:     stringdefault.StaticString(time.Minute.String()),
+		},
+		"images": schema.ListNestedAttribute{
+			Description: "Image overrides of the Flux components written to the kustomization.yaml in the repository. Conflicts with `kustomization_override`.",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						Description: "Name of the image to override, e.g. `ghcr.io/fluxcd/source-controller`.",
+						Required:    true,
+					},
+					"new_name": schema.StringAttribute{
+						Description: "Name replacing the name of the image.",
+						Optional:    true,
+					},
+					"new_tag": schema.StringAttribute{
+						Description: "Tag replacing the tag of the image.",
+						Optional:    true,
+					},
+					"digest": schema.StringAttribute{
+						Description: "Digest replacing the tag of the image.",
+						Optional:    true,
+					},
 				},
 			},
-			"manifests_path": schema.StringAttribute{
-				Description:        fmt.Sprintf("The install manifests are built from a GitHub release or kustomize overlay if using a local path. Defaults to `%s`.", defaultOpts.BaseURL),
-				Optional:           true,
-				DeprecationMessage: "This attribute is deprecated. Use the `embedded_manifests` attribute when running bootstrap on air-gapped environments.",
+		},
+		"keep_namespace": schema.BoolAttribute{
+			Description: "Keep the namespace after uninstalling Flux components. Defaults to `false`.",
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+		},
+		"kustomization_override": schema.StringAttribute{
+			CustomType:  customtypes.YAMLType{},
+			Description: "Kustomization to override configuration set by default.",
+			Optional:    true,
+			Validators: []validator.String{
+				validators.KustomizationOverride(),
+				stringvalidator.ConflictsWith(path.MatchRoot("patches"), path.MatchRoot("images")),
 			},
-			"namespace": schema.StringAttribute{
-				Description: fmt.Sprintf("The namespace scope for install manifests. Defaults to `%s`. It will be created if it does not exist.", defaultOpts.Namespace),
-				Optional:    true,
-				Computed:    true,
-				Default:     stringdefault.StaticString(defaultOpts.Namespace),
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+		},
+		"manifests_path": schema.StringAttribute{
+			Description:        fmt.Sprintf("The install manifests are built from a GitHub release or kustomize overlay if using a local path. Defaults to `%s`.", defaultOpts.BaseURL),
+			Optional:           true,
+			DeprecationMessage: "This attribute is deprecated. Use the `embedded_manifests` attribute when running bootstrap on air-gapped environments.",
+		},
+		"open_pull_request": schema.SingleNestedAttribute{
+			Description: "Pull request opened by an update which has not been merged yet. Later updates push to its branch instead of opening another pull request, and the repository files are read from its branch until it is merged or closed.",
+			Attributes: map[string]schema.Attribute{
+				"branch": schema.StringAttribute{
+					Description: "Branch the changes have been pushed to.",
+					Computed:    true,
 				},
-				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(rfc1123LabelRegex), rfc1123LabelError),
-					stringvalidator.LengthAtMost(63),
+				"number": schema.Int64Attribute{
+					Description: "Number of the pull request.",
+					Computed:    true,
 				},
-			},
-			"network_policy": schema.BoolAttribute{
-				Description: fmt.Sprintf("Deny ingress access to the toolkit controllers from other namespaces using network policies. Defaults to `%v`.", defaultOpts.NetworkPolicy),
-				Optional:    true,
-				Computed:    true,
-				Default:     booldefault.StaticBool(defaultOpts.NetworkPolicy),
-			},
-			"path": schema.StringAttribute{
-				Description: "Path relative to the repository root, when specified the cluster sync will be scoped to this path (immutable).",
-				Optional:    true,
-			},
-			"recurse_submodules": schema.BoolAttribute{
-				Description: "Configures the GitRepository source to initialize and include Git submodules in the artifact it produces.",
-				Optional:    true,
-			},
-			"registry": schema.StringAttribute{
-				CustomType:  customtypes.URLType{},
-				Description: fmt.Sprintf("Container registry where the toolkit images are published. Defaults to `%s`.", defaultOpts.Registry),
-				Optional:    true,
-				Computed:    true,
-				Default:     stringdefault.StaticString(defaultOpts.Registry),
-			},
-			"registry_credentials": schema.StringAttribute{
-				Description: "Container registry credentials in the format 'user:password'",
-				Optional:    true,
-			},
-			"repository_files": schema.MapAttribute{
-				ElementType: types.StringType,
-				Description: "Git repository files created and managed by the provider.",
-				Computed:    true,
-			},
-			"secret_name": schema.StringAttribute{
-				Description: fmt.Sprintf("Name of the secret the sync credentials can be found in or stored to. Defaults to `%s`.", defaultOpts.Namespace),
-				Optional:    true,
-				Computed:    true,
-				Default:     stringdefault.StaticString(defaultOpts.Namespace),
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
-				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(rfc1123DomainRegex), rfc1123DomainError),
-					stringvalidator.LengthAtMost(253),
+				"url": schema.StringAttribute{
+					Description: "URL of the pull request.",
+					Computed:    true,
 				},
 			},
-			"timeouts": timeouts.AttributesAll(ctx),
-			"toleration_keys": schema.SetAttribute{
-				ElementType: types.StringType,
-				Description: "List of toleration keys used to schedule the components pods onto nodes with matching taints.",
-				Optional:    true,
-				Validators: []validator.Set{
-					setvalidator.ValueStringsAre(
-						stringvalidator.RegexMatches(regexp.MustCompile(tolerationKeyRegex), tolerationKeyError),
-						stringvalidator.LengthAtMost(253),
-					),
+			Computed: true,
+		},
+		"patches": schema.ListNestedAttribute{
+			Description: "Patches of the Flux manifests written to the kustomization.yaml in the repository. Conflicts with `kustomization_override`.",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"patch": schema.StringAttribute{
+						CustomType:  customtypes.YAMLType{},
+						Description: "Strategic merge or JSON 6902 patch.",
+						Required:    true,
+					},
+					"target": schema.SingleNestedAttribute{
+						Description: "Objects the patch is applied to, required by JSON 6902 patches. Strategic merge patches are applied to the object they name when not set.",
+						Optional:    true,
+						Attributes: map[string]schema.Attribute{
+							"group": schema.StringAttribute{
+								Description: "API group of the objects.",
+								Optional:    true,
+							},
+							"version": schema.StringAttribute{
+								Description: "API version of the objects.",
+								Optional:    true,
+							},
+							"kind": schema.StringAttribute{
+								Description: "Kind of the objects, e.g. `Deployment`.",
+								Optional:    true,
+							},
+							"name": schema.StringAttribute{
+								Description: "Name of the objects, which can be a regular expression.",
+								Optional:    true,
+							},
+							"namespace": schema.StringAttribute{
+								Description: "Namespace of the objects.",
+								Optional:    true,
+							},
+							"label_selector": schema.StringAttribute{
+								Description: "Label selector of the objects, e.g. `app.kubernetes.io/part-of=flux`.",
+								Optional:    true,
+							},
+							"annotation_selector": schema.StringAttribute{
+								Description: "Annotation selector of the objects.",
+								Optional:    true,
+							},
+						},
+					},
 				},
 			},
-			"version": schema.StringAttribute{
-				Description: fmt.Sprintf("Flux version. Defaults to `%s`. Has no effect when `embedded_manifests` is enabled.", utils.DefaultFluxVersion),
-				Optional:    true,
-				Computed:    true,
-				Default:     stringdefault.StaticString(utils.DefaultFluxVersion),
-				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile("(latest|^v.*)"), "must either be latest or start with 'v'"),
-				},
+		},
+		"path": schema.StringAttribute{
+			Description: "Path relative to the repository root, when specified the cluster sync will be scoped to this path (immutable).",
+			Optional:    true,
+		},
+		"recurse_submodules": schema.BoolAttribute{
+			Description: "Configures the GitRepository source to initialize and include Git submodules in the artifact it produces.",
+			Optional:    true,
+		},
+		"drift": schema.ListAttribute{
+			ElementType: types.StringType,
+			Description: "Changes of the Kubernetes objects in the repository files compared to the expected manifests, detected when refreshing the state. Each entry names the file, the object and the changed fields.",
+			Computed:    true,
+		},
+		"repository_files": schema.MapAttribute{
+			ElementType: customtypes.YAMLType{},
+			Description: "Git repository files created and managed by the provider.",
+			Computed:    true,
+		},
+		"secret_name": schema.StringAttribute{
+			Description: fmt.Sprintf("Name of the secret the sync credentials can be found in or stored to. Defaults to `%s`.", defaultOpts.Namespace),
+			Optional:    true,
+			Computed:    true,
+			Default:     stringdefault.StaticString(defaultOpts.Namespace),
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
 			},
-			"watch_all_namespaces": schema.BoolAttribute{
-				Description: fmt.Sprintf("If true watch for custom resources in all namespaces. Defaults to `%v`.", defaultOpts.WatchAllNamespaces),
-				Optional:    true,
-				Computed:    true,
-				Default:     booldefault.StaticBool(defaultOpts.WatchAllNamespaces),
+			Validators: []validator.String{
+				stringvalidator.RegexMatches(regexp.MustCompile(rfc1123DomainRegex), rfc1123DomainError),
+				stringvalidator.LengthAtMost(253),
 			},
 		},
+		"timeouts": timeouts.AttributesAll(ctx),
+	})
+	providerAttributes := providerSchema().Attributes
+	for name, description := range map[string]string{
+		"git":        "Git configuration replacing the `git` block of the provider, which allows bootstrapping repositories with `for_each`.",
+		"kubernetes": "Kubernetes configuration replacing the `kubernetes` block of the provider, which allows bootstrapping clusters with `for_each`.",
+	} {
+		override, diags := overrideAttribute(path.Root(name), providerAttributes[name], description)
+		resp.Diagnostics.Append(diags...)
+		attributes[name] = override
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Commits Flux components to a Git repository and configures a Kubernetes cluster to synchronize with the same Git repository.",
+		Attributes:          attributes,
+	}
+}
+
+// overrideAttribute converts a provider attribute to the optional resource attribute overriding it.
+func overrideAttribute(p path.Path, a providerschema.Attribute, description string) (schema.Attribute, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	nested, ok := a.(providerschema.SingleNestedAttribute)
+	if !ok {
+		diags.AddAttributeError(p, "Unsupported provider attribute", fmt.Sprintf("Expected a single nested attribute to override, got %T.", a))
+		return nil, diags
+	}
+	attributes, diags := convertProviderAttributes(p, nested.Attributes)
+	return schema.SingleNestedAttribute{
+		Attributes:          attributes,
+		CustomType:          nested.CustomType,
+		Description:         description,
+		MarkdownDescription: description,
+		DeprecationMessage:  nested.DeprecationMessage,
+		Optional:            true,
+		Sensitive:           nested.Sensitive,
+		Validators:          nested.Validators,
+	}, diags
+}
+
+func convertProviderAttributes(p path.Path, providerAttributes map[string]providerschema.Attribute) (map[string]schema.Attribute, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	attributes := map[string]schema.Attribute{}
+	for k, v := range providerAttributes {
+		a, d := convertProviderAttribute(p.AtName(k), v)
+		diags.Append(d...)
+		if a != nil {
+			attributes[k] = a
+		}
+	}
+	return attributes, diags
+}
+
+func convertProviderAttribute(p path.Path, a providerschema.Attribute) (schema.Attribute, diag.Diagnostics) {
+	switch a := a.(type) {
+	case providerschema.StringAttribute:
+		return schema.StringAttribute{
+			CustomType:          a.CustomType,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Sensitive:           a.Sensitive,
+			Validators:          a.Validators,
+		}, nil
+	case providerschema.BoolAttribute:
+		return schema.BoolAttribute{
+			CustomType:          a.CustomType,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Sensitive:           a.Sensitive,
+			Validators:          a.Validators,
+		}, nil
+	case providerschema.Float64Attribute:
+		return schema.Float64Attribute{
+			CustomType:          a.CustomType,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Sensitive:           a.Sensitive,
+			Validators:          a.Validators,
+		}, nil
+	case providerschema.Int64Attribute:
+		return schema.Int64Attribute{
+			CustomType:          a.CustomType,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Sensitive:           a.Sensitive,
+			Validators:          a.Validators,
+		}, nil
+	case providerschema.ListAttribute:
+		return schema.ListAttribute{
+			ElementType:         a.ElementType,
+			CustomType:          a.CustomType,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Sensitive:           a.Sensitive,
+			Validators:          a.Validators,
+		}, nil
+	case providerschema.SetAttribute:
+		return schema.SetAttribute{
+			ElementType:         a.ElementType,
+			CustomType:          a.CustomType,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Sensitive:           a.Sensitive,
+			Validators:          a.Validators,
+		}, nil
+	case providerschema.MapAttribute:
+		return schema.MapAttribute{
+			ElementType:         a.ElementType,
+			CustomType:          a.CustomType,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Sensitive:           a.Sensitive,
+			Validators:          a.Validators,
+		}, nil
+	case providerschema.SingleNestedAttribute:
+		attributes, diags := convertProviderAttributes(p, a.Attributes)
+		return schema.SingleNestedAttribute{
+			Attributes:          attributes,
+			CustomType:          a.CustomType,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Sensitive:           a.Sensitive,
+			Validators:          a.Validators,
+		}, diags
+	default:
+		var diags diag.Diagnostics
+		diags.AddAttributeError(p, "Unsupported provider attribute", fmt.Sprintf("The provider attribute of type %T cannot be overridden by the resource.", a))
+		return nil, diags
 	}
 }
 
@@ -351,58 +556,88 @@ func (r *bootstrapGitResource) ValidateConfig(ctx context.Context, req resource.
 		return
 	}
 
-	if data.RegistryCredentials.ValueString() != "" && data.ImagePullSecret.ValueString() == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("registry_credentials"),
-			"Missing image_pull_secret configuration",
-			"The image_pull_secret attribute must be configured when registry_credential is set.",
-		)
-	}
-
-	if data.RegistryCredentials.ValueString() != "" && len(strings.Split(data.RegistryCredentials.ValueString(), ":")) != 2 {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("registry_credentials"),
-			"Invalid registry_credential format",
-			"Expected 'user:password' format.",
-		)
+	resp.Diagnostics.Append(validateInstallOptions(data.installOptionsData)...)
+	if data.Kubernetes != nil {
+		resp.Diagnostics.Append(validateKubernetes(data.Kubernetes)...)
 	}
-
-	// If registry_credential is not configured, return without warning.
-	if data.RegistryCredentials.IsNull() || data.RegistryCredentials.ValueString() == "" {
-		return
+	if data.Git != nil {
+		resp.Diagnostics.Append(validateGit(data.Git)...)
+		if data.Git.Ssh != nil && !data.Git.Ssh.HostKeyAlgos.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("git").AtName("ssh").AtName("hostkey_algos"),
+				"Unexpected Attribute Configuration",
+				"Did not expect hostkey_algos to be configured on the resource, the host key algorithms apply to all Git clients and can only be configured by the provider.",
+			)
+		}
 	}
 }
 
-// ModifyPlan sets the desired Git repository files to be managed by the provider.
+// ModifyPlan sets the desired Git repository files to be managed by the provider. The files are
+// unknown when the provider configuration or the overrides contain values unknown during plan.
 func (r bootstrapGitResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
-	if r.prd == nil {
-		resp.Diagnostics.AddError(missingConfiguration, bootstrapGitResourceMissingConfigError)
+	// Skip when deleting.
+	if req.Plan.Raw.IsNull() {
+		return
 	}
 
-	// Skip when deleting or on initial creation.
-	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+	overridesKnown, diags := isOverrideConfigKnown(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if (r.prd != nil && r.prd.unknown) || !overridesKnown {
+		tflog.Debug(ctx, "Deferring repository files to apply as the configuration is unknown", map[string]interface{}{})
+		if !req.State.Raw.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("repository_files"), types.MapUnknown(customtypes.YAMLType{}))...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("drift"), noDrift())...)
+		}
 		return
 	}
 
 	var data bootstrapGitResourceData
-	diags := req.Plan.Get(ctx, &data)
+	diags = req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	prd, diags := r.getProviderResourceData(ctx, data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Write expected repository files.
-	repositoryFiles, err := getExpectedRepositoryFiles(data, r.prd.GetRepositoryURL(), r.prd.git.Branch.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Getting expected repository files", err.Error())
+	// Build the kustomization override or patches, also on initial creation, to report errors before anything is pushed.
+	validateOverride := hasCustomization(data) && !data.KustomizationOverride.IsUnknown() && !data.Path.IsUnknown() && !data.Namespace.IsUnknown()
+	if req.State.Raw.IsNull() && !validateOverride {
 		return
 	}
-	mapValue, diags := types.MapValueFrom(ctx, types.StringType, repositoryFiles)
+	repositoryFiles, diags := getExpectedRepositoryFiles(ctx, data, prd.GetRepositoryURL(), prd.git.Branch.ValueString(), prd.GetRootSourceOptions())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if validateOverride {
+		dir := filepath.ToSlash(filepath.Join(data.Path.ValueString(), data.Namespace.ValueString()))
+		resp.Diagnostics.Append(validateKustomizationBuild(ctx, customizationPath(data), repositoryFiles, dir)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Skip on initial creation.
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	// Write expected repository files.
+	mapValue, diags := types.MapValueFrom(ctx, customtypes.YAMLType{}, repositoryFiles)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 	data.RepositoryFiles = mapValue
+	// Applying the plan writes the expected files to the repository, which resolves any drift.
+	data.Drift = noDrift()
 
 	diags = resp.Plan.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -411,16 +646,17 @@ func (r bootstrapGitResource) ModifyPlan(ctx context.Context, req resource.Modif
 // Create pushes the Flux manifests in the Git repository, installs the Flux controllers on the cluster
 // and configures Flux to sync the cluster state with the given Git repository path.
 func (r *bootstrapGitResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	if r.prd == nil {
-		resp.Diagnostics.AddError(missingConfiguration, bootstrapGitResourceMissingConfigError)
-	}
-
 	var data bootstrapGitResourceData
 	diags := req.Plan.Get(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	prd, diags := r.getProviderResourceData(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	timeout, diags := data.Timeouts.Create(ctx, defaultCreateTimeout)
 	resp.Diagnostics.Append(diags...)
@@ -430,21 +666,21 @@ func (r *bootstrapGitResource) Create(ctx context.Context, req resource.CreateRe
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	kubeClient, err := r.prd.GetKubernetesClient()
+	kubeClient, err := prd.GetKubernetesClient()
 	if err != nil {
 		resp.Diagnostics.AddError("Kubernetes Client", err.Error())
 		return
 	}
 
-	gitClient, err := r.prd.CloneRepository(ctx)
+	gitClient, err := prd.CloneRepository(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Git Client", err.Error())
 		return
 	}
 	defer func() { _ = os.RemoveAll(gitClient.Path()) }()
 
-	installOpts := getInstallOptions(data)
-	syncOpts := getSyncOptions(data, r.prd.GetRepositoryURL(), r.prd.git.Branch.ValueString())
+	installOpts := getInstallOptions(data.installOptionsData, data.ManifestsPath.ValueString(), data.Path.ValueString())
+	syncOpts := getBootstrapSyncOptions(data, prd.GetRepositoryURL(), prd.git.Branch.ValueString())
 	var secretOpts sourcesecret.Options
 	if data.DisableSecretCreation.ValueBool() {
 		secretOpts = sourcesecret.Options{
@@ -452,14 +688,23 @@ func (r *bootstrapGitResource) Create(ctx context.Context, req resource.CreateRe
 			Namespace: data.Namespace.ValueString(),
 		}
 	} else {
-		secretOpts, err = r.prd.GetSecretOptions(data.SecretName.ValueString(), data.Namespace.ValueString(), data.Path.ValueString())
+		secretOpts, err = prd.GetSecretOptions(data.SecretName.ValueString(), data.Namespace.ValueString(), data.Path.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError("Could not get secret options", err.Error())
 			return
 		}
+		secretOpts, err = prd.ReconcileSyncSecret(ctx, secretOpts)
+		if err != nil {
+			resp.Diagnostics.AddError("Could not reconcile sync secret", err.Error())
+			return
+		}
+	}
+	if err := prd.ReconcileProxySecret(ctx, data.Namespace.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Could not reconcile proxy secret", err.Error())
+		return
 	}
 
-	bootstrapOpts, err := r.prd.GetBootstrapOptions()
+	bootstrapOpts, err := prd.GetBootstrapOptions()
 	if err != nil {
 		resp.Diagnostics.AddError("Could not get bootstrap options", err.Error())
 		return
@@ -471,15 +716,20 @@ func (r *bootstrapGitResource) Create(ctx context.Context, req resource.CreateRe
 	}
 
 	// Write own kustomization file
-	if data.KustomizationOverride.ValueString() != "" {
+	if hasCustomization(data) || !prd.GetRootSourceOptions().isEmpty() {
+		kustomization, diags := getKustomizationFile(ctx, data, prd.GetRootSourceOptions())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 		// Need to write empty gotk-components and gotk-sync because otherwise Kustomize will not work.
 		basePath := filepath.Join(gitClient.Path(), data.Path.ValueString(), data.Namespace.ValueString())
 		files := map[string]io.Reader{
-			filepath.Join(basePath, konfig.DefaultKustomizationFileName()): strings.NewReader(data.KustomizationOverride.ValueString()),
+			filepath.Join(basePath, konfig.DefaultKustomizationFileName()): strings.NewReader(kustomization),
 			filepath.Join(basePath, installOpts.ManifestFile):              &strings.Reader{},
 			filepath.Join(basePath, syncOpts.ManifestFile):                 &strings.Reader{},
 		}
-		commit, signer, err := r.prd.CreateCommit("Init Flux with kustomize override")
+		commit, signer, err := prd.CreateCommit("Init Flux with kustomize override")
 		if err != nil {
 			resp.Diagnostics.AddError("Unable to create kustomize override commit", err.Error())
 			return
@@ -491,11 +741,7 @@ func (r *bootstrapGitResource) Create(ctx context.Context, req resource.CreateRe
 		}
 	}
 
-	manifestsBase := ""
-	if data.EmbeddedManifests.ValueBool() {
-		manifestsBase = EmbeddedManifests
-	}
-	err = bootstrap.Run(ctx, bootstrapProvider, manifestsBase, installOpts, secretOpts, syncOpts, 2*time.Second, timeout)
+	err = bootstrap.Run(ctx, bootstrapProvider, getManifestsBase(data.installOptionsData), installOpts, secretOpts, syncOpts, 2*time.Second, timeout)
 	if err != nil {
 		resp.Diagnostics.AddError("Bootstrap run error", err.Error())
 		return
@@ -512,33 +758,37 @@ func (r *bootstrapGitResource) Create(ctx context.Context, req resource.CreateRe
 		}
 		repositoryFiles[filePath] = string(b)
 	}
-	mapValue, diags := types.MapValueFrom(ctx, types.StringType, repositoryFiles)
+	mapValue, diags := types.MapValueFrom(ctx, customtypes.YAMLType{}, repositoryFiles)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 	data.RepositoryFiles = mapValue
+	data.Drift = noDrift()
+	data.OpenPullRequest = types.ObjectNull(openPullRequestType.AttrTypes)
 
 	data.ID = data.Namespace
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 }
 
-// Read pulls the Flux manifests from the Git repository to detect drift
-// and checks the health of the Flux controllers in the cluster.
-// If the Flux controllers are not healthy, the state is marked as needing an update.
+// Read pulls the Flux manifests from the Git repository to detect drift, checks the health of the
+// Flux controllers in the cluster and compares the controllers, CRDs and network policies with the
+// install manifests. If the Flux controllers are not healthy or have drifted, the state is marked
+// as needing an update.
 // TODO: Handle Git auth key rotation.
 func (r *bootstrapGitResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	if r.prd == nil {
-		resp.Diagnostics.AddError(missingConfiguration, bootstrapGitResourceMissingConfigError)
-	}
-
 	var data bootstrapGitResourceData
 	diags := req.State.Get(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	prd, diags := r.getProviderResourceData(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	timeout, diags := data.Timeouts.Read(ctx, defaultCreateTimeout)
 	resp.Diagnostics.Append(diags...)
@@ -548,7 +798,17 @@ func (r *bootstrapGitResource) Read(ctx context.Context, req resource.ReadReques
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	gitClient, err := r.prd.CloneRepository(ctx)
+	// The repository files are read from the branch of a pull request which is not merged yet,
+	// as it holds the changes of the last update.
+	openPR, diags := getOpenPullRequest(ctx, prd, data.OpenPullRequest)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if openPR == nil {
+		data.OpenPullRequest = types.ObjectNull(openPullRequestType.AttrTypes)
+	}
+	gitClient, err := prd.ClonePullRequestBranch(ctx, openPR.headBranch())
 	if err != nil {
 		resp.Diagnostics.AddError("Git Client", err.Error())
 		return
@@ -571,7 +831,36 @@ func (r *bootstrapGitResource) Read(ctx context.Context, req resource.ReadReques
 		repositoryFiles[k] = string(b)
 	}
 
-	kubeClient, err := r.prd.GetKubernetesClient()
+	// Describe the drift of the Kubernetes objects in the repository files, as the plan
+	// of the files themselves is hard to review.
+	expectedFiles, diags := getExpectedRepositoryFiles(ctx, data, prd.GetRepositoryURL(), prd.git.Branch.ValueString(), prd.GetRootSourceOptions())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	drift := []string{}
+	for _, k := range slices.Sorted(maps.Keys(data.RepositoryFiles.Elements())) {
+		diffs := diffRepositoryFile(repositoryFiles, expectedFiles, k)
+		if len(diffs) == 0 {
+			continue
+		}
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("repository_files").AtMapKey(k),
+			fmt.Sprintf("Repository file %s has drifted and will be updated", k),
+			strings.Join(diffs, "\n"),
+		)
+		for _, d := range diffs {
+			drift = append(drift, fmt.Sprintf("%s: %s", k, d))
+		}
+	}
+	driftValue, diags := types.ListValueFrom(ctx, types.StringType, drift)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Drift = driftValue
+
+	kubeClient, err := prd.GetKubernetesClient()
 	if err != nil {
 		resp.Diagnostics.AddError("Kubernetes Client", err.Error())
 		return
@@ -584,7 +873,7 @@ func (r *bootstrapGitResource) Read(ctx context.Context, req resource.ReadReques
 	}
 
 	// Detect drift for the Flux installation in the cluster.
-	ready, err := isFluxReady(ctx, kubeClient, data)
+	ready, err := isFluxReady(ctx, kubeClient, data.Namespace.ValueString(), &sourcev1.GitRepository{})
 	if !ready {
 		// reset the gotk_sync.yaml file content to simulate a Git drift which will trigger a redeployment
 		syncOpts := sync.MakeDefaultOptions()
@@ -600,7 +889,39 @@ func (r *bootstrapGitResource) Read(ctx context.Context, req resource.ReadReques
 		)
 	}
 
-	mapValue, diags := types.MapValueFrom(ctx, types.StringType, repositoryFiles)
+	// Detect drift for the Flux components in the cluster. Patches and images in the kustomization
+	// may change the controller Deployments, only their presence is checked then. The components
+	// are expected to differ from the state while a pull request with the changes is open.
+	if openPR == nil {
+		installOpts := getInstallOptions(data.installOptionsData, data.ManifestsPath.ValueString(), data.Path.ValueString())
+		manifest, err := install.Generate(installOpts, getManifestsBase(data.installOptionsData))
+		if err != nil {
+			resp.Diagnostics.AddError("Could not generate install manifests", err.Error())
+			return
+		}
+		objects, err := utils.ReadObjects(manifest.Content)
+		if err != nil {
+			resp.Diagnostics.AddError("Could not read install manifests", err.Error())
+			return
+		}
+		drifted, err := detectComponentDrift(ctx, kubeClient, objects, !hasCustomization(data))
+		if err != nil {
+			resp.Diagnostics.AddError("Could not detect drift of the Flux components", err.Error())
+			return
+		}
+		if len(drifted) > 0 {
+			// reset the gotk-components.yaml file content to trigger a redeployment of the components
+			componentsPath := filepath.Join(data.Path.ValueString(), data.Namespace.ValueString(), installOpts.ManifestFile)
+			repositoryFiles[componentsPath] = ""
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("repository_files").AtMapKey(componentsPath),
+				fmt.Sprintf("Flux components in %s namespace have drifted and Flux will be redeployed", data.Namespace.ValueString()),
+				strings.Join(drifted, "\n"),
+			)
+		}
+	}
+
+	mapValue, diags := types.MapValueFrom(ctx, customtypes.YAMLType{}, repositoryFiles)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -613,16 +934,17 @@ func (r *bootstrapGitResource) Read(ctx context.Context, req resource.ReadReques
 
 // Update pushes the Flux manifests in the Git repository and applies the changes on the cluster.
 func (r bootstrapGitResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	if r.prd == nil {
-		resp.Diagnostics.AddError(missingConfiguration, bootstrapGitResourceMissingConfigError)
-	}
-
 	var data bootstrapGitResourceData
 	diags := req.Plan.Get(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	prd, diags := r.getProviderResourceData(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	timeout, diags := data.Timeouts.Update(ctx, defaultUpdateTimeout)
 	resp.Diagnostics.Append(diags...)
@@ -632,7 +954,7 @@ func (r bootstrapGitResource) Update(ctx context.Context, req resource.UpdateReq
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	previousRepositoryFiles := types.MapNull(types.StringType)
+	previousRepositoryFiles := types.MapNull(customtypes.YAMLType{})
 	diags = req.State.GetAttribute(ctx, path.Root("repository_files"), &previousRepositoryFiles)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -644,10 +966,24 @@ func (r bootstrapGitResource) Update(ctx context.Context, req resource.UpdateReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	previousOpenPR := types.ObjectNull(openPullRequestType.AttrTypes)
+	diags = req.State.GetAttribute(ctx, path.Root("open_pull_request"), &previousOpenPR)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// Changes are added to the pull request opened by a previous update when it is still open.
+	openPR, diags := getOpenPullRequest(ctx, prd, previousOpenPR)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Sync Git repository with Terraform state.
+	var pr *forge.PullRequest
+	headBranch := openPR.headBranch()
 	err := retry.RetryContext(ctx, timeout, func() *retry.RetryError {
-		gitClient, err := r.prd.CloneRepository(ctx)
+		gitClient, err := prd.ClonePullRequestBranch(ctx, openPR.headBranch())
 		if err != nil {
 			return retry.NonRetryableError(err)
 		}
@@ -679,7 +1015,7 @@ func (r bootstrapGitResource) Update(ctx context.Context, req resource.UpdateReq
 		for k, v := range repositoryFiles {
 			files[k] = strings.NewReader(v)
 		}
-		commit, signer, err := r.prd.CreateCommit("Update Flux manifests")
+		commit, signer, err := prd.CreateCommit("Update Flux manifests")
 		if err != nil {
 			return retry.NonRetryableError(fmt.Errorf("unable to create commit: %w", err))
 		}
@@ -691,19 +1027,56 @@ func (r bootstrapGitResource) Update(ctx context.Context, req resource.UpdateReq
 		if err != nil {
 			return nil
 		}
-		err = gitClient.Push(ctx, repository.PushConfig{})
+		if openPR != nil {
+			if err := gitClient.Push(ctx, repository.PushConfig{}); err != nil {
+				return retry.RetryableError(fmt.Errorf("unable to push updated manifests: %w", err))
+			}
+			return nil
+		}
+		headBranch, err = prd.PushBranch(ctx, gitClient)
 		if err != nil {
 			return retry.RetryableError(fmt.Errorf("unable to push updated manifests: %w", err))
 		}
+		pr, err = prd.OpenPullRequest(ctx, headBranch, "Update Flux manifests")
+		if err != nil {
+			if headBranch != prd.git.Branch.ValueString() {
+				if err := prd.DeleteBranch(ctx, gitClient, headBranch); err != nil {
+					tflog.Warn(ctx, "Could not delete the branch of the pull request", map[string]interface{}{"branch": headBranch, "error": err.Error()})
+				}
+			}
+			return retry.NonRetryableError(err)
+		}
 		return nil
 	})
+	if openPR != nil {
+		pr = &forge.PullRequest{Number: int(openPR.Number.ValueInt64()), URL: openPR.URL.ValueString(), State: forge.StateOpen}
+		resp.Diagnostics.AddWarning("Pull request updated", fmt.Sprintf("The Flux manifests changes have been pushed to the open pull request %s.", pr.URL))
+	} else if pr != nil {
+		resp.Diagnostics.AddWarning("Pull request opened", fmt.Sprintf("The Flux manifests changes have been pushed to %s.", pr.URL))
+	}
+	merged := false
+	if err == nil {
+		merged, err = prd.WaitForPullRequest(ctx, pr)
+	}
+	// The pull request is recorded until it is merged, so that later updates push to its branch
+	// instead of opening another one.
+	switch {
+	case pr != nil && !merged:
+		data.OpenPullRequest, diags = openPullRequestValue(ctx, headBranch, pr)
+		resp.Diagnostics.Append(diags...)
+	case err == nil:
+		data.OpenPullRequest = types.ObjectNull(openPullRequestType.AttrTypes)
+	default:
+		data.OpenPullRequest = previousOpenPR
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Could not update Flux manifests in Git", err.Error())
-	} else {
-		// Sync Flux installation with Git state.
+	} else if merged {
+		// Sync Flux installation with Git state. Pull requests which are not waited for
+		// are applied by Flux itself once merged.
 
-		installOpts := getInstallOptions(data)
-		syncOpts := getSyncOptions(data, r.prd.GetRepositoryURL(), r.prd.git.Branch.ValueString())
+		installOpts := getInstallOptions(data.installOptionsData, data.ManifestsPath.ValueString(), data.Path.ValueString())
+		syncOpts := getBootstrapSyncOptions(data, prd.GetRepositoryURL(), prd.git.Branch.ValueString())
 		var secretOpts sourcesecret.Options
 		if data.DisableSecretCreation.ValueBool() {
 			secretOpts = sourcesecret.Options{
@@ -711,11 +1084,20 @@ func (r bootstrapGitResource) Update(ctx context.Context, req resource.UpdateReq
 				Namespace: data.Namespace.ValueString(),
 			}
 		} else {
-			secretOpts, err = r.prd.GetSecretOptions(data.SecretName.ValueString(), data.Namespace.ValueString(), data.Path.ValueString())
+			secretOpts, err = prd.GetSecretOptions(data.SecretName.ValueString(), data.Namespace.ValueString(), data.Path.ValueString())
 			if err != nil {
 				resp.Diagnostics.AddError("Could not get secret options", err.Error())
 				return
 			}
+			secretOpts, err = prd.ReconcileSyncSecret(ctx, secretOpts)
+			if err != nil {
+				resp.Diagnostics.AddError("Could not reconcile sync secret", err.Error())
+				return
+			}
+		}
+		if err := prd.ReconcileProxySecret(ctx, data.Namespace.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Could not reconcile proxy secret", err.Error())
+			return
 		}
 
 		tmpDir, err := manifestgen.MkdirTempAbs("", "flux-bootstrap-")
@@ -724,17 +1106,13 @@ func (r bootstrapGitResource) Update(ctx context.Context, req resource.UpdateReq
 		}
 		defer func() { _ = os.RemoveAll(tmpDir) }()
 
-		bootstrapProvider, err := r.prd.GetBootstrapProvider(tmpDir)
+		bootstrapProvider, err := prd.GetBootstrapProvider(tmpDir)
 		if err != nil {
 			resp.Diagnostics.AddError("Bootstrap Provider", err.Error())
 			return
 		}
 
-		manifestsBase := ""
-		if data.EmbeddedManifests.ValueBool() {
-			manifestsBase = EmbeddedManifests
-		}
-		err = bootstrap.Run(ctx, bootstrapProvider, manifestsBase, installOpts, secretOpts, syncOpts, 2*time.Second, timeout)
+		err = bootstrap.Run(ctx, bootstrapProvider, getManifestsBase(data.installOptionsData), installOpts, secretOpts, syncOpts, 2*time.Second, timeout)
 		if err != nil {
 			resp.Diagnostics.AddError("Bootstrap run error", err.Error())
 			return
@@ -745,18 +1123,24 @@ func (r bootstrapGitResource) Update(ctx context.Context, req resource.UpdateReq
 	resp.Diagnostics.Append(diags...)
 }
 
-// Delete removes the Flux components from the cluster and the manifests from the Git repository.
+// Delete removes the manifests from the Git repository and the Flux components from the cluster according to the deletion policy.
 func (r bootstrapGitResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	if r.prd == nil {
-		resp.Diagnostics.AddError(missingConfiguration, bootstrapGitResourceMissingConfigError)
-	}
-
 	var data bootstrapGitResourceData
 	diags := req.State.Get(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	// The resource is only removed from the state, neither the provider configuration nor the cluster is required.
+	if data.DeletionPolicy.ValueString() == deletionPolicyOrphan {
+		tflog.Debug(ctx, "Skipping removal of Flux as the deletion policy is orphan", map[string]interface{}{})
+		return
+	}
+	prd, diags := r.getProviderResourceData(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	timeout, diags := data.Timeouts.Delete(ctx, defaultDeleteTimeout)
 	resp.Diagnostics.Append(diags...)
@@ -766,47 +1150,33 @@ func (r bootstrapGitResource) Delete(ctx context.Context, req resource.DeleteReq
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	kubeClient, err := r.prd.GetKubernetesClient()
+	kubeClient, err := prd.GetKubernetesClient()
 	if err != nil {
 		resp.Diagnostics.AddError("Kubernetes Client", err.Error())
 		tflog.Error(ctx, "Unable to get Kubernetes client", map[string]interface{}{})
 		return
 	}
 
-	err = uninstall.Components(ctx, log.NopLogger{}, kubeClient, data.Namespace.ValueString(), false)
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to remove Flux components", err.Error())
-		tflog.Debug(ctx, "Unable to remove Flux components", map[string]interface{}{})
-	}
-	err = uninstall.Finalizers(ctx, log.NopLogger{}, kubeClient, false)
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to remove finalizers", err.Error())
-		tflog.Debug(ctx, "Unable to remove finalizers", map[string]interface{}{})
-	}
-	err = uninstall.CustomResourceDefinitions(ctx, log.NopLogger{}, kubeClient, false)
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to remove CRDs", err.Error())
-		tflog.Debug(ctx, "Unable to remove CRDs", map[string]interface{}{})
-	}
-
-	// Only remove namespace if not keeping it.
-	if data.KeepNamespace.ValueBool() {
-		tflog.Debug(ctx, fmt.Sprintf("The keep_namespace variable was set to true. Skipping removal of %s namespace.", data.Namespace.ValueString()), map[string]interface{}{})
-	} else {
-		err = uninstall.Namespace(ctx, log.NopLogger{}, kubeClient, data.Namespace.ValueString(), false)
-		if err != nil {
-			resp.Diagnostics.AddError(fmt.Sprintf("Unable to remove %s namespace.", data.Namespace.ValueString()), err.Error())
-			tflog.Debug(ctx, fmt.Sprintf("Unable to remove %s namespace.", data.Namespace.ValueString()), map[string]interface{}{})
-		}
-	}
-
 	if !(data.DeleteGitManifests.IsNull() || data.DeleteGitManifests.ValueBool()) { //nolint:all
 		tflog.Debug(ctx, "Skipping git repository removal", map[string]interface{}{})
+		resp.Diagnostics.Append(deleteFlux(ctx, kubeClient, data.Namespace.ValueString(), data.DeletionPolicy.ValueString(), data.KeepNamespace.ValueBool(), &sourcev1.GitRepository{})...)
 		return
 	}
 
+	// Flux keeps syncing while the manifests are removed from Git, and the root Kustomization would prune the
+	// controllers and CRDs. The root sync is suspended first unless Flux is uninstalled, which then only happens
+	// once the removal from Git is merged.
+	uninstallPolicy := data.DeletionPolicy.ValueString() == deletionPolicyUninstall
+	if !uninstallPolicy {
+		resp.Diagnostics.Append(suspendRootSync(ctx, kubeClient, data.Namespace.ValueString(), &sourcev1.GitRepository{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var pr *forge.PullRequest
 	err = retry.RetryContext(ctx, timeout, func() *retry.RetryError {
-		gitClient, err := r.prd.CloneRepository(ctx)
+		gitClient, err := prd.CloneRepository(ctx)
 		if err != nil {
 			return retry.NonRetryableError(err)
 		}
@@ -825,7 +1195,7 @@ func (r bootstrapGitResource) Delete(ctx context.Context, req resource.DeleteReq
 			}
 		}
 		// TODO: If no files are removed we should not commit anything.
-		commit, signer, err := r.prd.CreateCommit("Uninstall Flux")
+		commit, signer, err := prd.CreateCommit("Uninstall Flux")
 		if err != nil {
 			return retry.NonRetryableError(fmt.Errorf("unable to create commit: %w", err))
 		}
@@ -836,24 +1206,108 @@ func (r bootstrapGitResource) Delete(ctx context.Context, req resource.DeleteReq
 			return retry.NonRetryableError(fmt.Errorf("unable to commit removed file(s): %w", err))
 		}
 
-		err = gitClient.Push(ctx, repository.PushConfig{})
+		headBranch, err := prd.PushBranch(ctx, gitClient)
 		if err != nil {
 			return retry.RetryableError(fmt.Errorf("unable to push removed file(s): %w", err))
 		}
+		pr, err = prd.OpenPullRequest(ctx, headBranch, "Uninstall Flux")
+		if err != nil {
+			if headBranch != prd.git.Branch.ValueString() {
+				if err := prd.DeleteBranch(ctx, gitClient, headBranch); err != nil {
+					tflog.Warn(ctx, "Could not delete the branch of the pull request", map[string]interface{}{"branch": headBranch, "error": err.Error()})
+				}
+			}
+			return retry.NonRetryableError(err)
+		}
 		return nil
 	})
+	if pr != nil {
+		resp.Diagnostics.AddWarning("Pull request opened", fmt.Sprintf("The removal of the Flux manifests has been pushed to %s.", pr.URL))
+	}
+	merged := false
+	if err == nil {
+		merged, err = prd.WaitForPullRequest(ctx, pr)
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Could not delete Flux configuration from Git repository.", err.Error())
+		return
 	}
+	if !merged && uninstallPolicy {
+		resp.Diagnostics.AddWarning(
+			"Flux has not been uninstalled",
+			fmt.Sprintf("Flux is left installed in the %s namespace as the pull request removing its manifests is not merged yet. Uninstall it once the pull request is merged.", data.Namespace.ValueString()),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(deleteFlux(ctx, kubeClient, data.Namespace.ValueString(), data.DeletionPolicy.ValueString(), data.KeepNamespace.ValueBool(), &sourcev1.GitRepository{})...)
+}
+
+// getProviderResourceData returns the provider configuration with the git and kubernetes overrides of the resource applied.
+func (r *bootstrapGitResource) getProviderResourceData(ctx context.Context, data bootstrapGitResourceData) (*providerResourceData, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if r.prd != nil && r.prd.unknown && (data.Git == nil || data.Kubernetes == nil) {
+		diags.AddError("Unknown provider configuration", "The provider configuration contains values which are not known yet, they have to be known when applying.")
+		return nil, diags
+	}
+	prd, err := r.prd.WithOverrides(ctx, data.Git, data.Kubernetes)
+	if err != nil {
+		diags.AddError("Could not create provider resource data", err.Error())
+		return nil, diags
+	}
+	if prd == nil || prd.git == nil || prd.rcg == nil {
+		diags.AddError(missingConfiguration, bootstrapGitResourceMissingConfigError)
+		return nil, diags
+	}
+	return prd, diags
 }
 
-// ImportState scans the cluster for the Flux components configuration and imports it into the Terraform state.
+// isOverrideConfigKnown reports whether all values of the git and kubernetes overrides, and of the
+// patches and images the kustomization is generated from, are known.
+func isOverrideConfigKnown(ctx context.Context, config tfsdk.Config) (bool, diag.Diagnostics) {
+	for _, name := range []string{"git", "kubernetes", "patches", "images"} {
+		var override attr.Value
+		var diags diag.Diagnostics
+		if name == "patches" || name == "images" {
+			var list types.List
+			diags = config.GetAttribute(ctx, path.Root(name), &list)
+			override = list
+		} else {
+			var object types.Object
+			diags = config.GetAttribute(ctx, path.Root(name), &object)
+			override = object
+		}
+		if diags.HasError() {
+			return false, diags
+		}
+		v, err := override.ToTerraformValue(ctx)
+		if err != nil {
+			diags.AddAttributeError(path.Root(name), "Could not read configuration", err.Error())
+			return false, diags
+		}
+		if !v.IsFullyKnown() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ImportState scans the cluster and the Git repository for the Flux components configuration and imports it
+// into the Terraform state. The import ID is the namespace, optionally followed by the path in the repository.
 func (r *bootstrapGitResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	if r.prd == nil {
-		resp.Diagnostics.AddError(missingConfiguration, bootstrapGitResourceMissingConfigError)
+	// The git and kubernetes attributes of the resource are not available when importing,
+	// only the provider configuration is.
+	if r.prd == nil || r.prd.git == nil || r.prd.rcg == nil {
+		resp.Diagnostics.AddError(missingConfiguration, bootstrapGitResourceImportConfigError)
+		return
+	}
+	prd, diags := r.getProviderResourceData(ctx, bootstrapGitResourceData{})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	kubeClient, err := r.prd.GetKubernetesClient()
+	kubeClient, err := prd.GetKubernetesClient()
 	if err != nil {
 		resp.Diagnostics.AddError("Kubernetes Client", err.Error())
 		return
@@ -868,10 +1322,12 @@ func (r *bootstrapGitResource) ImportState(ctx context.Context, req resource.Imp
 			"update": types.StringType,
 		}),
 	}
-	data.ID = types.StringValue(req.ID)
+	// The import ID is the namespace, optionally followed by the path of the manifests in the repository.
+	namespace, importPath, _ := strings.Cut(req.ID, "/")
+	data.ID = types.StringValue(namespace)
 	data.Namespace = data.ID
 
-	ready, err := isFluxReady(ctx, kubeClient, data)
+	ready, err := isFluxReady(ctx, kubeClient, data.Namespace.ValueString(), &sourcev1.GitRepository{})
 	if err != nil {
 		resp.Diagnostics.AddError("Could not check Flux readiness", err.Error())
 		return
@@ -883,9 +1339,11 @@ func (r *bootstrapGitResource) ImportState(ctx context.Context, req resource.Imp
 
 	// Set values that cant be null.
 	data.TolerationKeys = types.SetNull(types.StringType)
+	data.OpenPullRequest = types.ObjectNull(openPullRequestType.AttrTypes)
 
 	// Stub keep namespace and delete git manifests to their defaults.
 	data.DeleteGitManifests = types.BoolValue(true)
+	data.DeletionPolicy = types.StringValue(deletionPolicyUninstall)
 	data.EmbeddedManifests = types.BoolValue(false)
 	data.KeepNamespace = types.BoolValue(false)
 
@@ -924,7 +1382,7 @@ func (r *bootstrapGitResource) ImportState(ctx context.Context, req resource.Imp
 	}
 
 	// Get Flux Version being used.
-	version, ok := kustomizeDeployment.Labels["app.kubernetes.io/version"]
+	version, ok := kustomizeDeployment.Labels[versionLabel]
 	if !ok {
 		resp.Diagnostics.AddError("Version label not found", "Label is not present in kustomize-controller Deployment")
 		return
@@ -937,13 +1395,8 @@ func (r *bootstrapGitResource) ImportState(ctx context.Context, req resource.Imp
 		resp.Diagnostics.AddError("Could not parse image reference", err.Error())
 		return
 	}
-	regstryStr := fmt.Sprintf("%s/%s", ref.Context().RegistryStr(), strings.Split(ref.Context().RepositoryStr(), "/")[0])
-	u, err := url.Parse(regstryStr)
-	if err != nil {
-		resp.Diagnostics.AddError("Could not parse url", err.Error())
-		return
-	}
-	data.Registry = customtypes.URLValue(u)
+	registry := fmt.Sprintf("%s/%s", ref.Context().RegistryStr(), strings.Split(ref.Context().RepositoryStr(), "/")[0])
+	data.Registry = customtypes.RegistryValue(registry)
 
 	// Get the toleration keys.
 	tolerationKeys := []string{}
@@ -1036,173 +1489,491 @@ func (r *bootstrapGitResource) ImportState(ctx context.Context, req resource.Imp
 	}
 	// Only set path value if path is something other than nil. This is to be consistent with the default value.
 	data.Path = types.StringNull()
-	syncPath := strings.TrimPrefix(kustomization.Spec.Path, "./")
+	syncPath := strings.Trim(strings.TrimPrefix(kustomization.Spec.Path, "./"), "/")
+	if importPath != "" {
+		syncPath = strings.Trim(importPath, "/")
+	}
 	if syncPath != "" {
 		data.Path = types.StringValue(syncPath)
 	}
 
-	// Check which components are present and which are not.
-	components := []attr.Value{}
-	for _, c := range install.MakeDefaultOptions().Components {
-		dep := appsv1.Deployment{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      c,
-				Namespace: data.Namespace.ValueString(),
-			},
-		}
-		err := kubeClient.Get(ctx, client.ObjectKeyFromObject(&dep), &dep)
-		if err != nil && k8serrors.IsNotFound(err) {
-			continue
-		}
-		if err != nil {
-			resp.Diagnostics.AddError(fmt.Sprintf("Could not get Deployment %s/%s", dep.Namespace, dep.Name), err.Error())
-			return
-		}
-		components = append(components, types.StringValue(c))
+	// Read the committed manifests, which take precedence over the values found in the cluster.
+	gitClient, err := prd.CloneRepository(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Git Client", err.Error())
+		return
 	}
-	componentsSet, diags := types.SetValue(types.StringType, components)
+	defer func() { _ = os.RemoveAll(gitClient.Path()) }()
+	repositoryFiles, diags := readRepositoryConfiguration(ctx, gitClient.Path(), prd.GetRootSourceOptions(), &data)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	data.Components = componentsSet
+	mapValue, diags := types.MapValueFrom(ctx, customtypes.YAMLType{}, repositoryFiles)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.RepositoryFiles = mapValue
+	data.Drift = noDrift()
 
-	componentsExtra := []attr.Value{}
-	for _, c := range install.MakeDefaultOptions().ComponentsExtra {
-		dep := appsv1.Deployment{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      c,
-				Namespace: data.Namespace.ValueString(),
-			},
-		}
-		err := kubeClient.Get(ctx, client.ObjectKeyFromObject(&dep), &dep)
-		if err != nil && k8serrors.IsNotFound(err) {
-			continue
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// readRepositoryConfiguration reads the Flux manifests committed to the repository and derives
+// the kustomization override, components, version and interval from them. The returned files
+// are the repository files as they are in the repository.
+func readRepositoryConfiguration(ctx context.Context, repoPath string, sourceOpts rootSourceOptions, data *bootstrapGitResourceData) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	basePath := filepath.Join(data.Path.ValueString(), data.Namespace.ValueString())
+	componentsPath := filepath.Join(basePath, install.MakeDefaultOptions().ManifestFile)
+	syncPath := filepath.Join(basePath, sync.MakeDefaultOptions().ManifestFile)
+	kustomizationPath := filepath.Join(basePath, konfig.DefaultKustomizationFileName())
+
+	repositoryFiles := map[string]string{}
+	for _, filePath := range []string{componentsPath, syncPath, kustomizationPath} {
+		b, err := os.ReadFile(filepath.Join(repoPath, filePath))
+		if errors.Is(err, os.ErrNotExist) {
+			diags.AddError(readRepositoryConfigurationError, fmt.Sprintf("Could not find %s in the repository, the path of the manifests can be set with an import ID in the format <namespace>/<path>.", filePath))
+			return nil, diags
 		}
 		if err != nil {
-			resp.Diagnostics.AddError(fmt.Sprintf("Could not get Deployment %s/%s", dep.Namespace, dep.Name), err.Error())
-			return
+			diags.AddError(readRepositoryConfigurationError, err.Error())
+			return nil, diags
 		}
-		componentsExtra = append(componentsExtra, types.StringValue(c))
+		repositoryFiles[filePath] = string(b)
 	}
-	componentsExtraSet, diags := types.SetValue(types.StringType, componentsExtra)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+
+	// The kustomization is imported as patches and images when it is generated from them,
+	// and as override otherwise.
+	data.KustomizationOverride = customtypes.YAMLNull()
+	data.Patches = types.ListNull(kustomizationPatchType)
+	data.Images = types.ListNull(kustomizationImageType)
+	kustomization := customtypes.YAMLValue(repositoryFiles[kustomizationPath])
+	generated, d := readCustomization(ctx, kustomization, sourceOpts, data)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	if !generated {
+		data.KustomizationOverride = readKustomizationOverride(ctx, kustomization, sourceOpts, *data)
+	}
+
+	componentObjects, err := utils.ReadObjects(repositoryFiles[componentsPath])
+	if err != nil {
+		diags.AddError(readRepositoryConfigurationError, fmt.Sprintf("Could not read %s: %s", componentsPath, err))
+		return nil, diags
+	}
+	components := []attr.Value{}
+	componentsExtra := []attr.Value{}
+	for _, obj := range componentObjects {
+		if obj.GetKind() != "Deployment" {
+			continue
+		}
+		if version, ok := obj.GetLabels()[versionLabel]; ok {
+			data.Version = types.StringValue(version)
+		}
+		switch {
+		case slices.Contains(install.MakeDefaultOptions().Components, obj.GetName()):
+			components = append(components, types.StringValue(obj.GetName()))
+		case slices.Contains(install.MakeDefaultOptions().ComponentsExtra, obj.GetName()):
+			componentsExtra = append(componentsExtra, types.StringValue(obj.GetName()))
+		}
 	}
+	data.Components = types.SetValueMust(types.StringType, components)
 	data.ComponentsExtra = types.SetNull(types.StringType)
 	if len(componentsExtra) > 0 {
-		data.ComponentsExtra = componentsExtraSet
+		data.ComponentsExtra = types.SetValueMust(types.StringType, componentsExtra)
 	}
 
-	// Set expected repository files.
-	repositoryFiles, err := getExpectedRepositoryFiles(data, r.prd.GetRepositoryURL(), r.prd.git.Branch.ValueString())
+	syncObjects, err := utils.ReadObjects(repositoryFiles[syncPath])
 	if err != nil {
-		resp.Diagnostics.AddError("Getting expected repository files", err.Error())
-		return
+		diags.AddError(readRepositoryConfigurationError, fmt.Sprintf("Could not read %s: %s", syncPath, err))
+		return nil, diags
 	}
-	mapValue, diags := types.MapValueFrom(ctx, types.StringType, repositoryFiles)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+	for _, obj := range syncObjects {
+		if obj.GetKind() != sourcev1.GitRepositoryKind {
+			continue
+		}
+		interval, ok, err := unstructured.NestedString(obj.Object, "spec", "interval")
+		if err != nil || !ok {
+			continue
+		}
+		d, err := time.ParseDuration(interval)
+		if err != nil {
+			diags.AddError(readRepositoryConfigurationError, fmt.Sprintf("Could not parse interval of %s: %s", utils.ObjectKey(obj), err))
+			return nil, diags
+		}
+		data.Interval = customtypes.DurationValue(d)
 	}
-	data.RepositoryFiles = mapValue
 
-	diags = resp.State.Set(ctx, &data)
-	resp.Diagnostics.Append(diags...)
+	return repositoryFiles, diags
+}
+
+// readCustomization sets the patches and images of the data to the ones the kustomization is generated
+// from. It reports false, leaving the data unchanged, when the provider does not generate the kustomization
+// from any patches and images, which is the case for kustomizations changed outside of the provider.
+func readCustomization(ctx context.Context, kustomization customtypes.YAML, sourceOpts rootSourceOptions, data *bootstrapGitResourceData) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	kus := kustypes.Kustomization{}
+	if err := yaml.Unmarshal([]byte(kustomization.ValueString()), &kus); err != nil {
+		return false, diags
+	}
+	// The patch of the root GitRepository comes first, it is added again when generating the kustomization.
+	patches := kus.Patches
+	if !sourceOpts.isEmpty() && len(patches) > 0 {
+		patches = patches[1:]
+	}
+
+	patchesData := []kustomizationPatch{}
+	for _, p := range patches {
+		patch := kustomizationPatch{Patch: customtypes.YAMLValue(p.Patch)}
+		if p.Target != nil {
+			patch.Target = &patchTarget{
+				Group:              stringValueOrNull(p.Target.Group),
+				Version:            stringValueOrNull(p.Target.Version),
+				Kind:               stringValueOrNull(p.Target.Kind),
+				Name:               stringValueOrNull(p.Target.Name),
+				Namespace:          stringValueOrNull(p.Target.Namespace),
+				LabelSelector:      stringValueOrNull(p.Target.LabelSelector),
+				AnnotationSelector: stringValueOrNull(p.Target.AnnotationSelector),
+			}
+		}
+		patchesData = append(patchesData, patch)
+	}
+	imagesData := []kustomizationImage{}
+	for _, i := range kus.Images {
+		imagesData = append(imagesData, kustomizationImage{
+			Name:    stringValueOrNull(i.Name),
+			NewName: stringValueOrNull(i.NewName),
+			NewTag:  stringValueOrNull(i.NewTag),
+			Digest:  stringValueOrNull(i.Digest),
+		})
+	}
+
+	candidate := *data
+	candidate.KustomizationOverride = customtypes.YAMLNull()
+	candidate.Patches = types.ListNull(kustomizationPatchType)
+	if len(patchesData) > 0 {
+		var d diag.Diagnostics
+		candidate.Patches, d = types.ListValueFrom(ctx, kustomizationPatchType, patchesData)
+		diags.Append(d...)
+	}
+	candidate.Images = types.ListNull(kustomizationImageType)
+	if len(imagesData) > 0 {
+		var d diag.Diagnostics
+		candidate.Images, d = types.ListValueFrom(ctx, kustomizationImageType, imagesData)
+		diags.Append(d...)
+	}
+	if diags.HasError() {
+		return false, diags
+	}
+
+	// The patches and images are only used when they produce the same kustomization, otherwise
+	// the plan would differ from the repository right after the import.
+	expected, d := getKustomizationFile(ctx, candidate, sourceOpts)
+	if d.HasError() {
+		return false, diags
+	}
+	equal, d := kustomization.StringSemanticEquals(ctx, customtypes.YAMLValue(expected))
+	diags.Append(d...)
+	if !equal || diags.HasError() {
+		return false, diags
+	}
+	data.Patches = candidate.Patches
+	data.Images = candidate.Images
+	return true, diags
 }
 
-func getKustomizationFile(data bootstrapGitResourceData) string {
+// readKustomizationOverride returns the override the kustomization is generated from, which is the
+// kustomization without the patch of the root GitRepository when there are source options.
+func readKustomizationOverride(ctx context.Context, kustomization customtypes.YAML, sourceOpts rootSourceOptions, data bootstrapGitResourceData) customtypes.YAML {
+	if sourceOpts.isEmpty() {
+		return kustomization
+	}
+	override, ok, err := removeFirstPatch(kustomization.ValueString())
+	if err != nil || !ok {
+		return kustomization
+	}
+	data.KustomizationOverride = customtypes.YAMLValue(override)
+	expected, diags := getKustomizationFile(ctx, data, sourceOpts)
+	if diags.HasError() {
+		return kustomization
+	}
+	equal, diags := kustomization.StringSemanticEquals(ctx, customtypes.YAMLValue(expected))
+	if !equal || diags.HasError() {
+		return kustomization
+	}
+	return data.KustomizationOverride
+}
+
+// stringValueOrNull returns a null value for empty strings, as unset optional attributes are null.
+func stringValueOrNull(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// getKustomizationFile returns the kustomization override, or the kustomization generated from the
+// patches and images. The patch of the root GitRepository with the source options comes first.
+func getKustomizationFile(ctx context.Context, data bootstrapGitResourceData, sourceOpts rootSourceOptions) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
 	if data.KustomizationOverride.ValueString() != "" {
-		return data.KustomizationOverride.ValueString()
+		if sourceOpts.isEmpty() {
+			return data.KustomizationOverride.ValueString(), diags
+		}
+		kustomization, err := prependPatch(data.KustomizationOverride.ValueString(), rootSourcePatch(data.Namespace.ValueString(), sourceOpts))
+		if err != nil {
+			diags.AddAttributeError(path.Root("kustomization_override"), "Could not generate kustomization", err.Error())
+		}
+		return kustomization, diags
+	}
+	if !hasCustomization(data) {
+		return getDefaultKustomizationFile(data.Namespace.ValueString(), sourceOpts), diags
+	}
+	patches, images, diags := getCustomization(ctx, data)
+	if diags.HasError() {
+		return "", diags
+	}
+	if !sourceOpts.isEmpty() {
+		patches = append([]kustypes.Patch{rootSourcePatch(data.Namespace.ValueString(), sourceOpts)}, patches...)
 	}
-	return `apiVersion: kustomize.config.k8s.io/v1beta1
+	kustomization, err := utils.GenerateKustomizationYaml([]string{install.MakeDefaultOptions().ManifestFile, sync.MakeDefaultOptions().ManifestFile}, patches, images)
+	if err != nil {
+		diags.AddAttributeError(customizationPath(data), "Could not generate kustomization", err.Error())
+	}
+	return kustomization, diags
+}
+
+// hasCustomization reports whether the Flux manifests are customized with a kustomization override,
+// patches or images.
+func hasCustomization(data bootstrapGitResourceData) bool {
+	return data.KustomizationOverride.ValueString() != "" || len(data.Patches.Elements()) > 0 || len(data.Images.Elements()) > 0
+}
+
+// customizationPath returns the attribute the kustomization is generated from.
+func customizationPath(data bootstrapGitResourceData) path.Path {
+	switch {
+	case data.KustomizationOverride.ValueString() != "":
+		return path.Root("kustomization_override")
+	case len(data.Patches.Elements()) > 0:
+		return path.Root("patches")
+	default:
+		return path.Root("images")
+	}
+}
+
+// getCustomization converts the patches and images attributes to their kustomize types.
+func getCustomization(ctx context.Context, data bootstrapGitResourceData) ([]kustypes.Patch, []kustypes.Image, diag.Diagnostics) {
+	var patchesData []kustomizationPatch
+	diags := data.Patches.ElementsAs(ctx, &patchesData, false)
+	var imagesData []kustomizationImage
+	diags.Append(data.Images.ElementsAs(ctx, &imagesData, false)...)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	patches := []kustypes.Patch{}
+	for _, p := range patchesData {
+		patch := kustypes.Patch{Patch: p.Patch.ValueString()}
+		if p.Target != nil {
+			patch.Target = &kustypes.Selector{
+				ResId: resid.ResId{
+					Gvk: resid.Gvk{
+						Group:   p.Target.Group.ValueString(),
+						Version: p.Target.Version.ValueString(),
+						Kind:    p.Target.Kind.ValueString(),
+					},
+					Name:      p.Target.Name.ValueString(),
+					Namespace: p.Target.Namespace.ValueString(),
+				},
+				LabelSelector:      p.Target.LabelSelector.ValueString(),
+				AnnotationSelector: p.Target.AnnotationSelector.ValueString(),
+			}
+		}
+		patches = append(patches, patch)
+	}
+	images := []kustypes.Image{}
+	for _, i := range imagesData {
+		images = append(images, kustypes.Image{
+			Name:    i.Name.ValueString(),
+			NewName: i.NewName.ValueString(),
+			NewTag:  i.NewTag.ValueString(),
+			Digest:  i.Digest.ValueString(),
+		})
+	}
+	return patches, images, diags
+}
+
+// rootSourceOptions are the fields of the root GitRepository which the sync manifests are generated without.
+type rootSourceOptions struct {
+	Provider        string
+	ProxySecretName string
+}
+
+func (o rootSourceOptions) isEmpty() bool {
+	return o == rootSourceOptions{}
+}
+
+// getDefaultKustomizationFile returns the kustomization of the Flux manifests. The root GitRepository
+// is patched with the source options when set.
+func getDefaultKustomizationFile(namespace string, sourceOpts rootSourceOptions) string {
+	kustomization := `apiVersion: kustomize.config.k8s.io/v1beta1
 kind: Kustomization
 resources:
 - gotk-components.yaml
 - gotk-sync.yaml
 `
+	if sourceOpts.isEmpty() {
+		return kustomization
+	}
+	ops := ""
+	for _, line := range strings.SplitAfter(rootSourcePatch(namespace, sourceOpts).Patch, "\n") {
+		if line != "" {
+			ops += "    " + line
+		}
+	}
+	return kustomization + fmt.Sprintf(`patches:
+- patch: |
+%s  target:
+    kind: GitRepository
+    name: %s
+`, ops, namespace)
 }
 
-func getInstallOptions(data bootstrapGitResourceData) install.Options {
-	components := []string{} //nolint:prealloc // ElementsAs replaces the slice, so preallocation is ineffective
-	data.Components.ElementsAs(context.Background(), &components, false)
-	sort.Strings(components)
-	componentsExtra := []string{}
-	data.ComponentsExtra.ElementsAs(context.Background(), &componentsExtra, false)
-	sort.Strings(componentsExtra)
-	components = append(components, componentsExtra...)
-
-	tolerationKeys := []string{}
-	data.TolerationKeys.ElementsAs(context.Background(), &tolerationKeys, false)
-	sort.Strings(tolerationKeys)
-
-	baseURL := data.ManifestsPath.ValueString()
-	if baseURL == "" {
-		baseURL = install.MakeDefaultOptions().BaseURL
-	}
-
-	installOptions := install.Options{
-		BaseURL:                baseURL,
-		ClusterDomain:          data.ClusterDomain.ValueString(),
-		Components:             components,
-		ImagePullSecret:        data.ImagePullSecret.ValueString(),
-		LogLevel:               data.LogLevel.ValueString(),
-		ManifestFile:           install.MakeDefaultOptions().ManifestFile,
-		Namespace:              data.Namespace.ValueString(),
-		NetworkPolicy:          data.NetworkPolicy.ValueBool(),
-		NotificationController: install.MakeDefaultOptions().NotificationController,
-		Registry:               data.Registry.ValueURL().String(),
-		RegistryCredential:     data.RegistryCredentials.ValueString(),
-		TargetPath:             data.Path.ValueString(),
-		Timeout:                install.MakeDefaultOptions().Timeout,
-		TolerationKeys:         tolerationKeys,
-		Version:                data.Version.ValueString(),
-		WatchAllNamespaces:     data.WatchAllNamespaces.ValueBool(),
-	}
-	return installOptions
+// rootSourcePatch returns the patch setting the source options on the root GitRepository.
+func rootSourcePatch(namespace string, sourceOpts rootSourceOptions) kustypes.Patch {
+	ops := ""
+	if sourceOpts.Provider != "" {
+		ops += fmt.Sprintf(`- op: add
+  path: /spec/provider
+  value: %s
+`, sourceOpts.Provider)
+	}
+	if sourceOpts.ProxySecretName != "" {
+		ops += fmt.Sprintf(`- op: add
+  path: /spec/proxySecretRef
+  value:
+    name: %s
+`, sourceOpts.ProxySecretName)
+	}
+	return kustypes.Patch{
+		Patch:  ops,
+		Target: &kustypes.Selector{ResId: resid.NewResIdKindOnly(sourcev1.GitRepositoryKind, namespace)},
+	}
 }
 
-func getSyncOptions(data bootstrapGitResourceData, url *url.URL, branch string) sync.Options {
-	syncOpts := sync.Options{
+// getBootstrapSyncOptions returns the sync options of the root source the repository is bootstrapped with.
+func getBootstrapSyncOptions(data bootstrapGitResourceData, url *url.URL, branch string) sync.Options {
+	return getSyncOptions(syncOptionsData{
 		Branch:            branch,
 		Interval:          data.Interval.ValueDuration(),
-		ManifestFile:      sync.MakeDefaultOptions().ManifestFile,
-		Name:              data.Namespace.ValueString(),
 		Namespace:         data.Namespace.ValueString(),
+		Path:              data.Path.ValueString(),
 		RecurseSubmodules: data.RecurseSubmodules.ValueBool(),
-		Secret:            data.SecretName.ValueString(),
-		TargetPath:        data.Path.ValueString(),
-		URL:               url.String(),
-	}
-	return syncOpts
+		SecretName:        data.SecretName.ValueString(),
+		URL:               url,
+	})
 }
 
-func getExpectedRepositoryFiles(data bootstrapGitResourceData, url *url.URL, branch string) (map[string]string, error) {
+func getExpectedRepositoryFiles(ctx context.Context, data bootstrapGitResourceData, url *url.URL, branch string, sourceOpts rootSourceOptions) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
 	repositoryFiles := map[string]string{}
-	installOpts := getInstallOptions(data)
-	manifestsBase := ""
-	if data.EmbeddedManifests.ValueBool() {
-		manifestsBase = EmbeddedManifests
-	}
-
-	installManifests, err := install.Generate(installOpts, manifestsBase)
+	installOpts := getInstallOptions(data.installOptionsData, data.ManifestsPath.ValueString(), data.Path.ValueString())
+	installManifests, err := install.Generate(installOpts, getManifestsBase(data.installOptionsData))
 	if err != nil {
-		return nil, fmt.Errorf("could not generate install manifests: %w", err)
+		diags.AddError("Getting expected repository files", fmt.Sprintf("could not generate install manifests: %s", err))
+		return nil, diags
 	}
 
 	repositoryFiles[installManifests.Path] = installManifests.Content
 
-	syncOpts := getSyncOptions(data, url, branch)
+	syncOpts := getBootstrapSyncOptions(data, url, branch)
 	syncManifests, err := sync.Generate(syncOpts)
 	if err != nil {
-		return nil, fmt.Errorf("could not generate sync manifests: %w", err)
+		diags.AddError("Getting expected repository files", fmt.Sprintf("could not generate sync manifests: %s", err))
+		return nil, diags
 	}
 
 	repositoryFiles[syncManifests.Path] = syncManifests.Content
-	repositoryFiles[filepath.Join(data.Path.ValueString(), data.Namespace.ValueString(), konfig.DefaultKustomizationFileName())] = getKustomizationFile(data)
+	kustomization, diags := getKustomizationFile(ctx, data, sourceOpts)
+	if diags.HasError() {
+		return nil, diags
+	}
+	repositoryFiles[filepath.Join(data.Path.ValueString(), data.Namespace.ValueString(), konfig.DefaultKustomizationFileName())] = kustomization
+
+	return repositoryFiles, diags
+}
+
+// diffRepositoryFile describes the changes of the Kubernetes objects in the repository file compared to
+// the expected file. Changes in formatting or comments are ignored.
+func diffRepositoryFile(repositoryFiles, expectedFiles map[string]string, filePath string) []string {
+	actual, ok := repositoryFiles[filePath]
+	if !ok {
+		return []string{"file is missing"}
+	}
+	expected, ok := expectedFiles[filePath]
+	if !ok || actual == expected {
+		return nil
+	}
+	diffs, err := utils.DiffObjects(actual, expected)
+	if err != nil {
+		return []string{fmt.Sprintf("file could not be parsed: %s", err)}
+	}
+	return diffs
+}
+
+// openPullRequest is the pull request recorded in the state while it is not merged.
+type openPullRequest struct {
+	Branch types.String `tfsdk:"branch"`
+	Number types.Int64  `tfsdk:"number"`
+	URL    types.String `tfsdk:"url"`
+}
 
-	return repositoryFiles, nil
+// headBranch returns the branch of the pull request, which is empty when there is none.
+func (o *openPullRequest) headBranch() string {
+	if o == nil {
+		return ""
+	}
+	return o.Branch.ValueString()
+}
+
+// getOpenPullRequest returns the pull request recorded in the state when it is still open.
+func getOpenPullRequest(ctx context.Context, prd *providerResourceData, value types.Object) (*openPullRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if value.IsNull() || value.IsUnknown() {
+		return nil, diags
+	}
+	var openPR openPullRequest
+	diags.Append(value.As(ctx, &openPR, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	pr, err := prd.GetOpenPullRequest(ctx, int(openPR.Number.ValueInt64()))
+	if err != nil {
+		diags.AddError("Could not get pull request", err.Error())
+		return nil, diags
+	}
+	if pr == nil {
+		return nil, diags
+	}
+	return &openPR, diags
+}
+
+// openPullRequestValue returns the state value of a pull request opened from the given branch.
+func openPullRequestValue(ctx context.Context, branch string, pr *forge.PullRequest) (types.Object, diag.Diagnostics) {
+	return types.ObjectValueFrom(ctx, openPullRequestType.AttrTypes, openPullRequest{
+		Branch: types.StringValue(branch),
+		Number: types.Int64Value(int64(pr.Number)),
+		URL:    types.StringValue(pr.URL),
+	})
+}
+
+// noDrift returns the drift of the resource after the expected files are written to the repository.
+func noDrift() types.List {
+	return types.ListValueMust(types.StringType, []attr.Value{})
 }
 
 // isKubernetesReady checks if the Kubernetes API is accessible
@@ -1217,19 +1988,22 @@ func isKubernetesReady(ctx context.Context, kubeClient client.Client) error {
 }
 
 // isFluxReady checks if the Flux sync objects are present and ready.
-func isFluxReady(ctx context.Context, kubeClient client.Client, data bootstrapGitResourceData) (bool, error) {
-	ns := data.Namespace.ValueString()
+// The root source is the empty object of the kind Flux syncs the cluster from.
+func isFluxReady(ctx context.Context, kubeClient client.Client, namespace string, rootSource conditions.Getter) (bool, error) {
 	syncName := apitypes.NamespacedName{
-		Namespace: ns,
-		Name:      ns,
+		Namespace: namespace,
+		Name:      namespace,
 	}
 
-	rootSource := &sourcev1.GitRepository{}
+	gvk, err := apiutil.GVKForObject(rootSource, kubeClient.Scheme())
+	if err != nil {
+		return false, err
+	}
 	if err := kubeClient.Get(ctx, syncName, rootSource); err != nil {
 		return false, err
 	}
 	if conditions.IsFalse(rootSource, meta.ReadyCondition) {
-		return false, fmt.Errorf("GitRepository/%s: %s", ns, conditions.GetMessage(rootSource, meta.ReadyCondition))
+		return false, fmt.Errorf("%s/%s: %s", gvk.Kind, namespace, conditions.GetMessage(rootSource, meta.ReadyCondition))
 	}
 
 	rootSync := &kustomizev1.Kustomization{}
@@ -1237,8 +2011,7 @@ func isFluxReady(ctx context.Context, kubeClient client.Client, data bootstrapGi
 		return false, err
 	}
 	if conditions.IsFalse(rootSync, meta.ReadyCondition) {
-		conditions.GetMessage(rootSync, meta.ReadyCondition)
-		return false, fmt.Errorf("Kustomization/%s: %s", ns, conditions.GetMessage(rootSync, meta.ReadyCondition)) //nolint:all
+		return false, fmt.Errorf("Kustomization/%s: %s", namespace, conditions.GetMessage(rootSync, meta.ReadyCondition)) //nolint:all
 	}
 
 	return true, nil
@@ -30,6 +30,8 @@ import (
 	"time"
 
 	"code.gitea.io/sdk/gitea"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
@@ -44,19 +46,29 @@ import (
 	"github.com/fluxcd/pkg/ssh"
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	"github.com/go-logr/logr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	cp "github.com/otiai10/copy"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/kind/pkg/cluster"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
 
+	customtypes "github.com/fluxcd/terraform-provider-flux/internal/framework/types"
 	"github.com/fluxcd/terraform-provider-flux/internal/utils"
 )
 
@@ -118,6 +130,240 @@ resources:
 	})
 }
 
+func TestAccBootstrapGit_InvalidCustomizationBuild(t *testing.T) {
+	brokenPatch := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - gotk-components.yaml
+  - gotk-sync.yaml
+patches:
+  - patch: |
+      - op: replace
+        path: /spec/template/spec/missing/field
+        value: true
+    target:
+      kind: Deployment
+      name: source-controller`
+	invalidImage := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - gotk-components.yaml
+  - gotk-sync.yaml
+images:
+  - name: ghcr.io/fluxcd/source-controller
+    newTag: "@latest"`
+	env := environment{
+		httpClone: "https://git.example",
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      bootstrapGitCustomization(env, brokenPatch),
+				ExpectError: regexp.MustCompile("Could not build kustomization"),
+			},
+			{
+				Config:      bootstrapGitCustomization(env, invalidImage),
+				ExpectError: regexp.MustCompile("Invalid image"),
+			},
+			{
+				Config: bootstrapGitPatches(env, `
+      patches = [{
+        patch = <<EOT
+- op: replace
+  path: /spec/template/spec/missing/field
+  value: true
+EOT
+        target = {
+          kind = "Deployment"
+          name = "source-controller"
+        }
+      }]`),
+				ExpectError: regexp.MustCompile("Could not build kustomization"),
+			},
+			{
+				Config: bootstrapGitPatches(env, `
+      kustomization_override = "kind: Kustomization"
+      images = [{
+        name    = "ghcr.io/fluxcd/source-controller"
+        new_tag = "v1.0.0"
+      }]`),
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+		},
+	})
+}
+
+func TestAccBootstrapGit_GitHubAppInvalidKey(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "flux" {
+				  kubernetes = {
+				    config_path = "kubeconfig"
+				  }
+				  git = {
+				    url = "https://github.com/fluxcd/fleet.git"
+				    github_app = {
+				      app_id          = "1234"
+				      installation_id = "42"
+				      private_key     = "invalid"
+				    }
+				  }
+				}
+				resource "flux_bootstrap_git" "this" {}
+				`,
+				ExpectError: regexp.MustCompile("Invalid GitHub App private key"),
+			},
+		},
+	})
+}
+
+func TestAccBootstrapGit_BearerTokenWithBasicAuth(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "flux" {
+				  kubernetes = {
+				    config_path = "kubeconfig"
+				  }
+				  git = {
+				    url = "https://dev.azure.com/fluxcd/fleet/_git/fleet"
+				    http = {
+				      username     = "git"
+				      bearer_token = "token"
+				    }
+				  }
+				}
+				resource "flux_bootstrap_git" "this" {}
+				`,
+				ExpectError: regexp.MustCompile("Did not expect username or password to be configured when bearer_token"),
+			},
+		},
+	})
+}
+
+func TestAccBootstrapGit_InvalidProxy(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "flux" {
+				  kubernetes = {
+				    config_path = "kubeconfig"
+				  }
+				  git = {
+				    url = "https://github.com/fluxcd/fleet.git"
+				    http = {
+				      password = "token"
+				    }
+				    proxy = {
+				      url = "ftp://proxy.example.com:3128"
+				    }
+				  }
+				}
+				resource "flux_bootstrap_git" "this" {}
+				`,
+				ExpectError: regexp.MustCompile("Invalid URL scheme"),
+			},
+			{
+				Config: `
+				provider "flux" {
+				  kubernetes = {
+				    config_path = "kubeconfig"
+				  }
+				  git = {
+				    url = "https://github.com/fluxcd/fleet.git"
+				    http = {
+				      password = "token"
+				    }
+				    proxy = {
+				      url      = "http://proxy.example.com:3128"
+				      password = "secret"
+				    }
+				  }
+				}
+				resource "flux_bootstrap_git" "this" {}
+				`,
+				ExpectError: regexp.MustCompile("Expected username to be configured when password is set"),
+			},
+		},
+	})
+}
+
+func TestAccBootstrapGit_InvalidKubernetes(t *testing.T) {
+	tests := []struct {
+		kubernetes    string
+		expectedError string
+	}{
+		{
+			kubernetes:    `as_groups = ["system:masters"]`,
+			expectedError: "Expected as to be configured when as_groups is set",
+		},
+		{
+			kubernetes: `insecure        = true
+				    tls_server_name = "kubernetes.default.svc"`,
+			expectedError: "Did not expect tls_server_name to be configured when insecure is\\s+true",
+		},
+		{
+			kubernetes:    `in_cluster = true`,
+			expectedError: "Did not expect config_path to be configured when in_cluster is true",
+		},
+		{
+			kubernetes:    `qps = 50`,
+			expectedError: "Expected burst to be configured when qps is set",
+		},
+		{
+			kubernetes:    `request_timeout = "one minute"`,
+			expectedError: "could not parse duration",
+		},
+	}
+	steps := []resource.TestStep{}
+	for _, tt := range tests {
+		// The provider and the resource overriding it validate the Kubernetes configuration alike.
+		steps = append(steps, resource.TestStep{
+			Config: fmt.Sprintf(`
+				provider "flux" {
+				  kubernetes = {
+				    config_path = "kubeconfig"
+				    %s
+				  }
+				  git = {
+				    url = "https://github.com/fluxcd/fleet.git"
+				  }
+				}
+				resource "flux_bootstrap_git" "this" {}
+				`, tt.kubernetes),
+			ExpectError: regexp.MustCompile(tt.expectedError),
+		}, resource.TestStep{
+			Config: fmt.Sprintf(`
+				provider "flux" {
+				  git = {
+				    url = "https://github.com/fluxcd/fleet.git"
+				  }
+				}
+				resource "flux_bootstrap_git" "this" {
+				  kubernetes = {
+				    config_path = "kubeconfig"
+				    %s
+				  }
+				}
+				`, tt.kubernetes),
+			ExpectError: regexp.MustCompile(tt.expectedError),
+		})
+	}
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps:                    steps,
+	})
+}
+
 func TestAccBootstrapGit_TolerationKeys(t *testing.T) {
 	env := environment{
 		httpClone: "https://git.example",
@@ -161,6 +407,42 @@ func TestAccBootstrapGit_HTTP(t *testing.T) {
 	})
 }
 
+func TestAccBootstrapGit_ConfigurationOverride(t *testing.T) {
+	env := setupEnvironment(t)
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: bootstrapGitConfigurationOverride(env),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/kustomization.yaml"),
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/gotk-components.yaml"),
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/gotk-sync.yaml"),
+				),
+			},
+			{
+				Config:   bootstrapGitConfigurationOverride(env),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccBootstrapGit_UnknownConfiguration(t *testing.T) {
+	env := setupEnvironment(t)
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: bootstrapGitUnknownConfiguration(env),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/gotk-sync.yaml"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccBootstrapGit_SSH(t *testing.T) {
 	env := setupEnvironment(t)
 	resource.ParallelTest(t, resource.TestCase{
@@ -185,6 +467,231 @@ func TestAccBootstrapGit_SSH(t *testing.T) {
 	})
 }
 
+func TestAccBootstrapGit_SSHKnownHosts(t *testing.T) {
+	env := setupEnvironment(t)
+	// Pin a key that does not belong to the Git server.
+	invalidKnownHosts := fmt.Sprintf("%s %s", strings.Fields(env.sshHostKey)[0], env.publicKey)
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      bootstrapGitSSHKnownHosts(env, invalidKnownHosts),
+				ExpectError: regexp.MustCompile("does not match the configured known_hosts"),
+			},
+			{
+				Config: bootstrapGitSSHKnownHosts(env, env.sshHostKey),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/gotk-sync.yaml"),
+					func(s *terraform.State) error {
+						cfg, err := clientcmd.BuildConfigFromFlags("", env.kubeCfgPath)
+						if err != nil {
+							return err
+						}
+						kubeClient, err := crclient.New(cfg, crclient.Options{Scheme: utils.NewScheme()})
+						if err != nil {
+							return err
+						}
+						secret := &corev1.Secret{}
+						if err := kubeClient.Get(context.Background(), crclient.ObjectKey{Name: "flux-system", Namespace: "flux-system"}, secret); err != nil {
+							return err
+						}
+						if strings.TrimSpace(string(secret.Data["known_hosts"])) != strings.TrimSpace(env.sshHostKey) {
+							return fmt.Errorf("expected known_hosts %q, got %q", env.sshHostKey, string(secret.Data["known_hosts"]))
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccBootstrapGit_MissingBranch(t *testing.T) {
+	env := setupEnvironment(t)
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create the branch with an initial commit.
+			{
+				Config: bootstrapGitBranch(env, "orphan", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/gotk-sync.yaml"),
+					resource.TestMatchResourceAttr("flux_bootstrap_git.this", "repository_files.flux-system/gotk-sync.yaml", regexp.MustCompile("branch: orphan")),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBootstrapGit_BaseBranch(t *testing.T) {
+	env := setupEnvironment(t)
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create the branch from the default branch.
+			{
+				Config: bootstrapGitBranch(env, "flux", defaultBranch),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("flux_bootstrap_git.this", "repository_files.flux-system/gotk-sync.yaml", regexp.MustCompile("branch: flux")),
+					func(s *terraform.State) error {
+						gitClient := getTestGitClient(t, env.username, env.password)
+						_, err := gitClient.Clone(context.TODO(), env.httpClone, repository.CloneConfig{
+							CheckoutStrategy: repository.CheckoutStrategy{
+								Branch: "flux",
+							},
+						})
+						if err != nil {
+							return err
+						}
+						// The README created by the Gitea auto init must be present on the new branch.
+						_, err = os.Stat(filepath.Join(gitClient.Path(), "README.md"))
+						return err
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccBootstrapGit_SSHSigning(t *testing.T) {
+	env := setupEnvironment(t)
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: bootstrapGitSSHSigning(env),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/gotk-sync.yaml"),
+					func(s *terraform.State) error {
+						gitClient := getTestGitClient(t, env.username, env.password)
+						commit, err := gitClient.Clone(context.TODO(), env.httpClone, repository.CloneConfig{
+							CheckoutStrategy: repository.CheckoutStrategy{
+								Branch: defaultBranch,
+							},
+						})
+						if err != nil {
+							return err
+						}
+						if !strings.HasPrefix(commit.Signature, "-----BEGIN SSH SIGNATURE-----") {
+							return fmt.Errorf("expected commit %s to have an SSH signature, got %q", commit.Hash, commit.Signature)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccBootstrapGit_GpgKeyRingContent(t *testing.T) {
+	env := setupEnvironment(t)
+	entity, err := openpgp.NewEntity("Flux", "", "flux@example.com", nil)
+	require.NoError(t, err)
+	var keyRing strings.Builder
+	w, err := armor.Encode(&keyRing, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivate(w, nil))
+	require.NoError(t, w.Close())
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      bootstrapGitGpgKeyRingContent(env, keyRing.String(), "0000000000000000"),
+				ExpectError: regexp.MustCompile("no GPG keyring matching key id"),
+			},
+			{
+				Config: bootstrapGitGpgKeyRingContent(env, keyRing.String(), entity.PrimaryKey.KeyIdString()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/gotk-sync.yaml"),
+					func(s *terraform.State) error {
+						gitClient := getTestGitClient(t, env.username, env.password)
+						commit, err := gitClient.Clone(context.TODO(), env.httpClone, repository.CloneConfig{
+							CheckoutStrategy: repository.CheckoutStrategy{
+								Branch: defaultBranch,
+							},
+						})
+						if err != nil {
+							return err
+						}
+						if !strings.HasPrefix(commit.Signature, "-----BEGIN PGP SIGNATURE-----") {
+							return fmt.Errorf("expected commit %s to have a PGP signature, got %q", commit.Hash, commit.Signature)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccBootstrapGit_InvalidSigning(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "flux" {
+				  kubernetes = {
+				    config_path = "kubeconfig"
+				  }
+				  git = {
+				    url = "https://github.com/fluxcd/fleet.git"
+				    http = {
+				      password = "token"
+				    }
+				    signing = {
+				      format      = "ssh"
+				      private_key = "invalid"
+				    }
+				  }
+				}
+				resource "flux_bootstrap_git" "this" {}
+				`,
+				ExpectError: regexp.MustCompile("Invalid SSH signing key"),
+			},
+			{
+				Config: `
+				provider "flux" {
+				  kubernetes = {
+				    config_path = "kubeconfig"
+				  }
+				  git = {
+				    url = "https://github.com/fluxcd/fleet.git"
+				    http = {
+				      password = "token"
+				    }
+				    signing = {
+				      format = "gpg"
+				    }
+				  }
+				}
+				resource "flux_bootstrap_git" "this" {}
+				`,
+				ExpectError: regexp.MustCompile("Expected gpg_key_ring or gpg_key_ring_content to be configured"),
+			},
+			{
+				Config: `
+				provider "flux" {
+				  kubernetes = {
+				    config_path = "kubeconfig"
+				  }
+				  git = {
+				    url = "https://github.com/fluxcd/fleet.git"
+				    http = {
+				      password = "token"
+				    }
+				    gpg_key_ring_content = "invalid"
+				  }
+				}
+				resource "flux_bootstrap_git" "this" {}
+				`,
+				ExpectError: regexp.MustCompile("Invalid GPG key ring"),
+			},
+		},
+	})
+}
+
 func TestAccBootstrapGit_AirGapped(t *testing.T) {
 	env := setupEnvironment(t)
 	resource.ParallelTest(t, resource.TestCase{
@@ -250,6 +757,38 @@ func TestAccBootstrapGit_Drift(t *testing.T) {
 					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/gotk-sync.yaml"),
 				),
 			},
+			// Change the controller args in Git and expect the drift to be described per object.
+			{
+				PreConfig: func() {
+					gitClient := getTestGitClient(t, env.username, env.password)
+					_, err := gitClient.Clone(context.TODO(), env.httpClone, repository.CloneConfig{
+						CheckoutStrategy: repository.CheckoutStrategy{
+							Branch: defaultBranch,
+						},
+					})
+					require.NoError(t, err)
+					componentsPath := filepath.Join(gitClient.Path(), "flux-system/gotk-components.yaml")
+					b, err := os.ReadFile(componentsPath)
+					require.NoError(t, err)
+					err = os.WriteFile(componentsPath, []byte(strings.ReplaceAll(string(b), "--log-level=info", "--log-level=debug")), 0o644)
+					require.NoError(t, err)
+					_, err = gitClient.Commit(git.Commit{})
+					require.NoError(t, err)
+					err = gitClient.Push(context.TODO(), repository.PushConfig{})
+					require.NoError(t, err)
+				},
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("flux_bootstrap_git.this", "drift.0", regexp.MustCompile(`^flux-system/gotk-components.yaml: Deployment/flux-system/[a-z-]+ changed spec.template.spec.containers\[manager\].args$`)),
+				),
+			},
+			{
+				Config: bootstrapGitHTTP(env),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("flux_bootstrap_git.this", "drift.#", "0"),
+				),
+			},
 			// Remove GitRepository in-cluster and expect Terraform to correct drift.
 			{
 				PreConfig: func() {
@@ -279,6 +818,33 @@ func TestAccBootstrapGit_Drift(t *testing.T) {
 					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/gotk-sync.yaml"),
 				),
 			},
+			// Scale a controller to zero in-cluster and expect Terraform to correct drift.
+			{
+				PreConfig: func() {
+					kubeClient := getTestKubeClient(t, env.kubeCfgPath)
+					deployment := &appsv1.Deployment{}
+					err := kubeClient.Get(context.Background(), apitypes.NamespacedName{Name: "source-controller", Namespace: "flux-system"}, deployment)
+					require.NoError(t, err)
+					replicas := int32(0)
+					deployment.Spec.Replicas = &replicas
+					require.NoError(t, kubeClient.Update(context.Background(), deployment))
+				},
+				Config: bootstrapGitSSH(env),
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						kubeClient := getTestKubeClient(t, env.kubeCfgPath)
+						deployment := &appsv1.Deployment{}
+						err := kubeClient.Get(context.Background(), apitypes.NamespacedName{Name: "source-controller", Namespace: "flux-system"}, deployment)
+						if err != nil {
+							return err
+						}
+						if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 1 {
+							return fmt.Errorf("expected source-controller to be scaled back to 1 replica")
+						}
+						return nil
+					},
+				),
+			},
 			// Expect no-op when Git and in-cluster state are in sync.
 			{
 				Config:            bootstrapGitSSH(env),
@@ -291,6 +857,34 @@ func TestAccBootstrapGit_Drift(t *testing.T) {
 	})
 }
 
+func TestAccBootstrapGit_DeletionPolicy(t *testing.T) {
+	env := setupEnvironment(t)
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: bootstrapGitDeletionPolicy(env, "suspend"),
+			},
+		},
+		// Expect the controllers to keep running with the root sync objects suspended.
+		CheckDestroy: func(s *terraform.State) error {
+			kubeClient := getTestKubeClient(t, env.kubeCfgPath)
+			deployment := &appsv1.Deployment{}
+			if err := kubeClient.Get(context.Background(), apitypes.NamespacedName{Name: "source-controller", Namespace: "flux-system"}, deployment); err != nil {
+				return err
+			}
+			gitRepository := &sourcev1.GitRepository{}
+			if err := kubeClient.Get(context.Background(), apitypes.NamespacedName{Name: "flux-system", Namespace: "flux-system"}, gitRepository); err != nil {
+				return err
+			}
+			if !gitRepository.Spec.Suspend {
+				return fmt.Errorf("expected GitRepository to be suspended")
+			}
+			return nil
+		},
+	})
+}
+
 func TestAccBootstrapGit_Upgrade(t *testing.T) {
 	env := setupEnvironment(t)
 	resource.ParallelTest(t, resource.TestCase{
@@ -316,6 +910,41 @@ func TestAccBootstrapGit_Upgrade(t *testing.T) {
 	})
 }
 
+func TestAccBootstrapGit_PullRequest(t *testing.T) {
+	env := setupEnvironment(t)
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: bootstrapGitPullRequest(env, "v2.7.0", "info"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/gotk-components.yaml"),
+					resource.TestCheckNoResourceAttr("flux_bootstrap_git.this", "open_pull_request.number"),
+					testCheckPullRequests(env, 0),
+				),
+			},
+			// Updates are pushed to a new branch, the manifests are read from it until the
+			// pull request is merged.
+			{
+				Config: bootstrapGitPullRequest(env, utils.DefaultFluxVersion, "info"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "open_pull_request.number"),
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "open_pull_request.branch"),
+					testCheckPullRequests(env, 1),
+				),
+			},
+			// Later updates are pushed to the branch of the open pull request.
+			{
+				Config: bootstrapGitPullRequest(env, utils.DefaultFluxVersion, "debug"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "open_pull_request.number"),
+					testCheckPullRequests(env, 1),
+				),
+			},
+		},
+	})
+}
+
 func TestAccBootstrapGit_Components(t *testing.T) {
 	env := setupEnvironment(t)
 	resource.ParallelTest(t, resource.TestCase{
@@ -365,41 +994,272 @@ patches:
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: bootstrapGitCustomization(env, kustomizationOverride),
+				Config: bootstrapGitCustomization(env, kustomizationOverride),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/kustomization.yaml"),
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/gotk-components.yaml"),
+					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/gotk-sync.yaml"),
+					func(state *terraform.State) error {
+						cfg, err := clientcmd.BuildConfigFromFlags("", env.kubeCfgPath)
+						if err != nil {
+							t.Fatalf("Can not initialize kubeconfig: %s", err)
+						}
+						kClient, err := kubernetes.NewForConfig(cfg)
+						if err != nil {
+							t.Fatalf("Can not initialize kubeconfig: %s", err)
+						}
+						deploymentList, err := kClient.AppsV1().Deployments("flux-system").List(context.TODO(), metav1.ListOptions{})
+						if err != nil {
+							t.Fatalf("Can not list deployments: %s", err)
+						}
+						for _, deployment := range deploymentList.Items {
+							v, ok := deployment.Spec.Template.Annotations["cluster-autoscaler.kubernetes.io/safe-to-evict"]
+							if !ok {
+								return fmt.Errorf("expected annotation to be set in Deployment %s", deployment.Name)
+							}
+							if v != "true" {
+								return fmt.Errorf("expected annotation value to be true but was %s", v)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			// Expect the kustomization override to be read from Git on import.
+			{
+				Config:            bootstrapGitCustomization(env, kustomizationOverride),
+				ResourceName:      "flux_bootstrap_git.this",
+				ImportState:       true,
+				ImportStateId:     "flux-system",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccBootstrapGit_Patches(t *testing.T) {
+	env := setupEnvironment(t)
+	customization := `
+      patches = [{
+        patch = <<EOT
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: all
+spec:
+  template:
+    metadata:
+      annotations:
+        cluster-autoscaler.kubernetes.io/safe-to-evict: "true"
+EOT
+        target = {
+          kind           = "Deployment"
+          label_selector = "app.kubernetes.io/part-of=flux"
+        }
+      }]
+      images = [{
+        name     = "ghcr.io/fluxcd/source-controller"
+        new_name = "ghcr.io/fluxcd/source-controller"
+      }]`
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: bootstrapGitPatches(env, customization),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/kustomization.yaml"),
-					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/gotk-components.yaml"),
-					resource.TestCheckResourceAttrSet("flux_bootstrap_git.this", "repository_files.flux-system/gotk-sync.yaml"),
+					resource.TestMatchResourceAttr("flux_bootstrap_git.this", "repository_files.flux-system/kustomization.yaml", regexp.MustCompile(`labelSelector: app.kubernetes.io/part-of=flux`)),
+					resource.TestMatchResourceAttr("flux_bootstrap_git.this", "repository_files.flux-system/kustomization.yaml", regexp.MustCompile(`newName: ghcr.io/fluxcd/source-controller`)),
 					func(state *terraform.State) error {
-						cfg, err := clientcmd.BuildConfigFromFlags("", env.kubeCfgPath)
-						if err != nil {
-							t.Fatalf("Can not initialize kubeconfig: %s", err)
-						}
-						kClient, err := kubernetes.NewForConfig(cfg)
-						if err != nil {
-							t.Fatalf("Can not initialize kubeconfig: %s", err)
-						}
-						deploymentList, err := kClient.AppsV1().Deployments("flux-system").List(context.TODO(), metav1.ListOptions{})
-						if err != nil {
-							t.Fatalf("Can not list deployments: %s", err)
+						kubeClient := getTestKubeClient(t, env.kubeCfgPath)
+						deploymentList := &appsv1.DeploymentList{}
+						if err := kubeClient.List(context.TODO(), deploymentList, crclient.InNamespace("flux-system")); err != nil {
+							return fmt.Errorf("could not list deployments: %w", err)
 						}
 						for _, deployment := range deploymentList.Items {
-							v, ok := deployment.Spec.Template.Annotations["cluster-autoscaler.kubernetes.io/safe-to-evict"]
-							if !ok {
+							if deployment.Spec.Template.Annotations["cluster-autoscaler.kubernetes.io/safe-to-evict"] != "true" {
 								return fmt.Errorf("expected annotation to be set in Deployment %s", deployment.Name)
 							}
-							if v != "true" {
-								return fmt.Errorf("expected annotation value to be true but was %s", v)
-							}
 						}
 						return nil
 					},
 				),
 			},
+			// Expect no changes as the kustomization is generated from the same patches.
+			{
+				Config:   bootstrapGitPatches(env, customization),
+				PlanOnly: true,
+			},
+			// The patches and images are read back from the generated kustomization.
+			{
+				Config:            bootstrapGitPatches(env, customization),
+				ResourceName:      "flux_bootstrap_git.this",
+				ImportState:       true,
+				ImportStateId:     "flux-system",
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
 
+func TestReadCustomization(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name          string
+		kustomization string
+		sourceOpts    rootSourceOptions
+		generated     bool
+		patches       int
+		images        int
+	}{
+		{
+			name:          "default",
+			kustomization: getDefaultKustomizationFile("flux-system", rootSourceOptions{}),
+			generated:     true,
+		},
+		{
+			name:          "default with root source patch",
+			kustomization: getDefaultKustomizationFile("flux-system", rootSourceOptions{Provider: "github"}),
+			sourceOpts:    rootSourceOptions{Provider: "github"},
+			generated:     true,
+		},
+		{
+			name: "patches and images",
+			kustomization: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - gotk-components.yaml
+  - gotk-sync.yaml
+patches:
+  - patch: |
+      - op: add
+        path: /spec/template/spec/containers/0/args/-
+        value: --concurrent=10
+    target:
+      kind: Deployment
+      name: kustomize-controller
+images:
+  - name: ghcr.io/fluxcd/kustomize-controller
+    newTag: v1.5.0
+`,
+			generated: true,
+			patches:   1,
+			images:    1,
+		},
+		{
+			name: "patches after the root source patch",
+			kustomization: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- gotk-components.yaml
+- gotk-sync.yaml
+patches:
+- patch: |
+    - op: add
+      path: /spec/proxySecretRef
+      value:
+        name: flux-system-proxy
+  target:
+    kind: GitRepository
+    name: flux-system
+- patch: |
+    - op: add
+      path: /spec/template/spec/containers/0/args/-
+      value: --concurrent=10
+  target:
+    kind: Deployment
+`,
+			sourceOpts: rootSourceOptions{ProxySecretName: "flux-system-proxy"},
+			generated:  true,
+			patches:    1,
+		},
+		{
+			name: "additional resources",
+			kustomization: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- gotk-components.yaml
+- gotk-sync.yaml
+- extra.yaml
+`,
+		},
+		{
+			name: "missing root source patch",
+			kustomization: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- gotk-components.yaml
+- gotk-sync.yaml
+`,
+			sourceOpts: rootSourceOptions{Provider: "github"},
+		},
+		{
+			name:          "invalid",
+			kustomization: "resources: [",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := bootstrapGitResourceData{
+				KustomizationOverride: customtypes.YAMLNull(),
+				Namespace:             types.StringValue("flux-system"),
+				Patches:               types.ListNull(kustomizationPatchType),
+				Images:                types.ListNull(kustomizationImageType),
+			}
+			generated, diags := readCustomization(ctx, customtypes.YAMLValue(tt.kustomization), tt.sourceOpts, &data)
+			require.False(t, diags.HasError(), "%s", diags)
+			require.Equal(t, tt.generated, generated)
+			require.Len(t, data.Patches.Elements(), tt.patches)
+			require.Len(t, data.Images.Elements(), tt.images)
+			if !generated {
+				return
+			}
+			kustomization, diags := getKustomizationFile(ctx, data, tt.sourceOpts)
+			require.False(t, diags.HasError(), "%s", diags)
+			equal, diags := customtypes.YAMLValue(tt.kustomization).StringSemanticEquals(ctx, customtypes.YAMLValue(kustomization))
+			require.False(t, diags.HasError(), "%s", diags)
+			require.True(t, equal, kustomization)
+		})
+	}
+}
+
+func TestKustomizationOverrideRootSourcePatch(t *testing.T) {
+	ctx := context.Background()
+	override := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- gotk-components.yaml
+- gotk-sync.yaml
+patches:
+# Scale the controllers.
+- patch: |
+    - op: replace
+      path: /spec/replicas
+      value: 2
+  target:
+    kind: Deployment
+`
+	sourceOpts := rootSourceOptions{Provider: "github", ProxySecretName: "flux-system-proxy"}
+	data := bootstrapGitResourceData{
+		KustomizationOverride: customtypes.YAMLValue(override),
+		Namespace:             types.StringValue("flux-system"),
+		Patches:               types.ListNull(kustomizationPatchType),
+		Images:                types.ListNull(kustomizationImageType),
+	}
+	kustomization, diags := getKustomizationFile(ctx, data, sourceOpts)
+	require.False(t, diags.HasError(), "%s", diags)
+	kus := kustypes.Kustomization{}
+	require.NoError(t, yaml.Unmarshal([]byte(kustomization), &kus))
+	require.Len(t, kus.Patches, 2)
+	require.Equal(t, rootSourcePatch("flux-system", sourceOpts), kus.Patches[0])
+	require.Contains(t, kustomization, "# Scale the controllers.")
+
+	// The override is read back without the patch of the root GitRepository.
+	imported := readKustomizationOverride(ctx, customtypes.YAMLValue(kustomization), sourceOpts, data)
+	equal, diags := imported.StringSemanticEquals(ctx, customtypes.YAMLValue(override))
+	require.False(t, diags.HasError(), "%s", diags)
+	require.True(t, equal, imported.ValueString())
+}
+
 func TestAccBootstrapGit_WithExistingSecret(t *testing.T) {
 	env := setupEnvironment(t)
 	namespace := &corev1.Namespace{
@@ -534,6 +1394,123 @@ func bootstrapGitHTTP(env environment) string {
 	`, env.kubeCfgPath, env.httpClone, env.username, env.password)
 }
 
+func bootstrapGitSSHSigning(env environment) string {
+	return fmt.Sprintf(`
+    provider "flux" {
+	  kubernetes = {
+        config_path = "%s"
+	  }
+	  git = {
+        url = "%s"
+        http = {
+          username = "%s"
+          password = "%s"
+          allow_insecure_http = true
+        }
+        signing = {
+          format = "ssh"
+          private_key = <<EOF
+%s
+EOF
+        }
+	  }
+    }
+
+    resource "flux_bootstrap_git" "this" {}
+	`, env.kubeCfgPath, env.httpClone, env.username, env.password, env.privateKey)
+}
+
+func bootstrapGitGpgKeyRingContent(env environment, keyRing, keyID string) string {
+	return fmt.Sprintf(`
+    provider "flux" {
+	  kubernetes = {
+        config_path = "%s"
+	  }
+	  git = {
+        url = "%s"
+        http = {
+          username = "%s"
+          password = "%s"
+          allow_insecure_http = true
+        }
+        gpg_key_ring_content = <<EOF
+%s
+EOF
+        gpg_key_id = "%s"
+	  }
+    }
+
+    resource "flux_bootstrap_git" "this" {}
+	`, env.kubeCfgPath, env.httpClone, env.username, env.password, keyRing, keyID)
+}
+
+func bootstrapGitConfigurationOverride(env environment) string {
+	return fmt.Sprintf(`
+    provider "flux" {}
+
+    resource "flux_bootstrap_git" "this" {
+      kubernetes = {
+        config_path = "%s"
+      }
+      git = {
+        url = "%s"
+        http = {
+          username = "%s"
+          password = "%s"
+          allow_insecure_http = true
+        }
+      }
+    }
+	`, env.kubeCfgPath, env.httpClone, env.username, env.password)
+}
+
+// bootstrapGitUnknownConfiguration configures the provider with values which are only known after apply.
+func bootstrapGitUnknownConfiguration(env environment) string {
+	return fmt.Sprintf(`
+    resource "terraform_data" "cluster" {
+      input = "%s"
+    }
+
+    provider "flux" {
+	  kubernetes = {
+        config_path = terraform_data.cluster.output
+	  }
+	  git = {
+        url = "%s"
+        http = {
+          username = "%s"
+          password = "%s"
+          allow_insecure_http = true
+        }
+	  }
+    }
+
+    resource "flux_bootstrap_git" "this" {}
+	`, env.kubeCfgPath, env.httpClone, env.username, env.password)
+}
+
+func bootstrapGitDeletionPolicy(env environment, deletionPolicy string) string {
+	return fmt.Sprintf(`
+    provider "flux" {
+	  kubernetes = {
+        config_path = "%s"
+	  }
+	  git = {
+        url = "%s"
+        http = {
+          username = "%s"
+          password = "%s"
+          allow_insecure_http = true
+        }
+	  }
+    }
+
+    resource "flux_bootstrap_git" "this" {
+      deletion_policy = "%s"
+    }
+	`, env.kubeCfgPath, env.httpClone, env.username, env.password, deletionPolicy)
+}
+
 func bootstrapGitSSH(env environment) string {
 	return fmt.Sprintf(`
     provider "flux" {
@@ -558,6 +1535,52 @@ EOF
 	`, env.kubeCfgPath, env.sshClone, env.privateKey)
 }
 
+func bootstrapGitSSHKnownHosts(env environment, knownHosts string) string {
+	return fmt.Sprintf(`
+    provider "flux" {
+	  kubernetes = {
+        config_path = "%s"
+	  }
+	  git = {
+        url = "%s"
+        ssh = {
+          username = "git"
+          private_key = <<EOF
+%s
+EOF
+          known_hosts = <<EOF
+%s
+EOF
+        }
+	  }
+    }
+
+    resource "flux_bootstrap_git" "this" {}
+	`, env.kubeCfgPath, env.sshClone, env.privateKey, knownHosts)
+}
+
+func bootstrapGitBranch(env environment, branch, baseBranch string) string {
+	return fmt.Sprintf(`
+    provider "flux" {
+	  kubernetes = {
+        config_path = "%s"
+	  }
+	  git = {
+        url = "%s"
+        branch = "%s"
+        base_branch = %q
+        http = {
+          username = "%s"
+          password = "%s"
+          allow_insecure_http = true
+        }
+	  }
+    }
+
+    resource "flux_bootstrap_git" "this" {}
+	`, env.kubeCfgPath, env.httpClone, branch, baseBranch, env.username, env.password)
+}
+
 func bootstrapAirGapped(env environment) string {
 	return fmt.Sprintf(`
     provider "flux" {
@@ -604,6 +1627,56 @@ func bootstrapGitVersion(env environment, version string) string {
 	`, env.kubeCfgPath, env.httpClone, env.username, env.password, version)
 }
 
+func bootstrapGitPullRequest(env environment, version, logLevel string) string {
+	return fmt.Sprintf(`
+    provider "flux" {
+	  kubernetes = {
+        config_path = "%s"
+	  }
+	  git = {
+        url = "%s"
+        http = {
+          username = "%s"
+          password = "%s"
+          allow_insecure_http = true
+        }
+        pull_request = {
+          provider = "gitea"
+          token    = "%s"
+        }
+	  }
+    }
+
+    resource "flux_bootstrap_git" "this" {
+      version   = "%s"
+      log_level = "%s"
+    }
+	`, env.kubeCfgPath, env.httpClone, env.username, env.password, env.token, version, logLevel)
+}
+
+// testCheckPullRequests checks the number of open pull requests against the default branch.
+func testCheckPullRequests(env environment, count int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		giteaClient, err := gitea.NewClient(env.giteaURL, gitea.SetToken(env.token))
+		if err != nil {
+			return err
+		}
+		prs, _, err := giteaClient.ListRepoPullRequests(env.username, env.repository, gitea.ListPullRequestsOptions{State: gitea.StateOpen})
+		if err != nil {
+			return err
+		}
+		if len(prs) != count {
+			return fmt.Errorf("expected %d open pull requests, got %d", count, len(prs))
+		}
+		for _, pr := range prs {
+			if pr.Base.Ref != defaultBranch {
+				return fmt.Errorf("expected pull request %d to target %s, got %s", pr.Index, defaultBranch, pr.Base.Ref)
+			}
+		}
+		return nil
+	}
+}
+
 func bootstrapGitCustomization(env environment, kustomizationOverride string) string {
 	return fmt.Sprintf(`
     provider "flux" {
@@ -628,6 +1701,28 @@ func bootstrapGitCustomization(env environment, kustomizationOverride string) st
 	`, env.kubeCfgPath, env.httpClone, env.username, env.password, kustomizationOverride)
 }
 
+func bootstrapGitPatches(env environment, customization string) string {
+	return fmt.Sprintf(`
+    provider "flux" {
+	  kubernetes = {
+        config_path = "%s"
+	  }
+	  git = {
+        url = "%s"
+        http = {
+          username = "%s"
+          password = "%s"
+          allow_insecure_http = true
+        }
+      }
+    }
+
+    resource "flux_bootstrap_git" "this" {
+%s
+    }
+	`, env.kubeCfgPath, env.httpClone, env.username, env.password, customization)
+}
+
 func bootstrapGitComponents(env environment) string {
 	return fmt.Sprintf(`
     provider "flux" {
@@ -684,6 +1779,9 @@ EOF
 
 type environment struct {
 	kubeCfgPath  string
+	giteaURL     string
+	repository   string
+	token        string
 	httpClone    string
 	sshClone     string
 	username     string
@@ -810,6 +1908,11 @@ func setupEnvironment(t *testing.T) environment {
 	repo, _, err := giteaClient.AdminCreateRepo(username, createRepoOpt)
 	require.NoError(t, err)
 
+	userClient, err := gitea.NewClient(giteaUrl, gitea.SetBasicAuth(username, password))
+	require.NoError(t, err)
+	token, _, err := userClient.CreateAccessToken(gitea.CreateAccessTokenOption{Name: "terraform"})
+	require.NoError(t, err)
+
 	keyPair, err := ssh.GenerateKeyPair(ssh.ECDSA_P256)
 	require.NoError(t, err)
 	createPublicKeyOpt := gitea.CreateKeyOption{
@@ -834,6 +1937,9 @@ func setupEnvironment(t *testing.T) environment {
 	require.NoError(t, err)
 	return environment{
 		kubeCfgPath:  kubeCfgPath,
+		giteaURL:     giteaUrl,
+		repository:   randSuffix,
+		token:        token.Token,
 		httpClone:    repo.CloneURL,
 		sshClone:     fmt.Sprintf("ssh://git@%s:%d/%s/%s.git", giteaName, sshPort, username, randSuffix),
 		username:     username,
@@ -846,6 +1952,15 @@ func setupEnvironment(t *testing.T) environment {
 	}
 }
 
+func getTestKubeClient(t *testing.T, kubeCfgPath string) crclient.Client {
+	t.Helper()
+	cfg, err := clientcmd.BuildConfigFromFlags("", kubeCfgPath)
+	require.NoError(t, err)
+	kubeClient, err := crclient.New(cfg, crclient.Options{Scheme: utils.NewScheme()})
+	require.NoError(t, err)
+	return kubeClient
+}
+
 func getTestGitClient(t *testing.T, username, password string) *gogit.Client {
 	t.Helper()
 	tmpDir, err := manifestgen.MkdirTempAbs("", "flux-bootstrap-")
@@ -862,3 +1977,25 @@ func getTestGitClient(t *testing.T, username, password string) *gogit.Client {
 	})
 	return gitClient
 }
+
+func TestOverrideAttribute(t *testing.T) {
+	providerAttributes := providerSchema().Attributes
+	a, diags := overrideAttribute(path.Root("git"), providerAttributes["git"], "Git override.")
+	require.False(t, diags.HasError(), diags)
+	override := a.(schema.SingleNestedAttribute)
+	require.True(t, override.Optional)
+	require.Equal(t, "Git override.", override.MarkdownDescription)
+	require.True(t, override.Attributes["http"].(schema.SingleNestedAttribute).Attributes["password"].IsSensitive())
+
+	_, diags = overrideAttribute(path.Root("git"), providerschema.StringAttribute{Optional: true}, "Git override.")
+	require.True(t, diags.HasError())
+
+	_, diags = overrideAttribute(path.Root("git"), providerschema.SingleNestedAttribute{
+		Optional: true,
+		Attributes: map[string]providerschema.Attribute{
+			"list": providerschema.ListNestedAttribute{Optional: true},
+		},
+	}, "Git override.")
+	require.True(t, diags.HasError())
+	require.Equal(t, path.Root("git").AtName("list"), diags[0].(diag.DiagnosticWithPath).Path())
+}
@@ -0,0 +1,592 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/flux2/v2/pkg/manifestgen/install"
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/pkg/apis/meta"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	customtypes "github.com/fluxcd/terraform-provider-flux/internal/framework/types"
+	"github.com/fluxcd/terraform-provider-flux/internal/framework/validators"
+	"github.com/fluxcd/terraform-provider-flux/internal/utils"
+)
+
+const (
+	defaultOCITag = "latest"
+
+	bootstrapOCIResourceMissingConfigError = "Kubernetes configuration not found"
+)
+
+type bootstrapOCIResourceData struct {
+	installOptionsData
+	CertSecretName types.String         `tfsdk:"cert_secret_name"`
+	Digest         types.String         `tfsdk:"digest"`
+	ID             types.String         `tfsdk:"id"`
+	Insecure       types.Bool           `tfsdk:"insecure"`
+	Interval       customtypes.Duration `tfsdk:"interval"`
+	Inventory      types.Map            `tfsdk:"inventory"`
+	KeepNamespace  types.Bool           `tfsdk:"keep_namespace"`
+	Password       types.String         `tfsdk:"password"`
+	Path           types.String         `tfsdk:"path"`
+	Provider       types.String         `tfsdk:"provider"`
+	SecretName     types.String         `tfsdk:"secret_name"`
+	Semver         types.String         `tfsdk:"semver"`
+	Tag            types.String         `tfsdk:"tag"`
+	Timeouts       timeouts.Value       `tfsdk:"timeouts"`
+	Url            customtypes.URL      `tfsdk:"url"`
+	Username       types.String         `tfsdk:"username"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &bootstrapOCIResource{}
+var _ resource.ResourceWithConfigure = &bootstrapOCIResource{}
+var _ resource.ResourceWithModifyPlan = &bootstrapOCIResource{}
+var _ resource.ResourceWithValidateConfig = &bootstrapOCIResource{}
+
+type bootstrapOCIResource struct {
+	prd *providerResourceData
+}
+
+func NewBootstrapOCIResource() resource.Resource {
+	return &bootstrapOCIResource{}
+}
+
+func (r *bootstrapOCIResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	prd, ok := req.ProviderData.(*providerResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.prd = prd
+}
+
+func (r *bootstrapOCIResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bootstrap_oci"
+}
+
+func (r *bootstrapOCIResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	defaultOpts := install.MakeDefaultOptions()
+	attributes, diags := installSchemaAttributes(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	maps.Copy(attributes, map[string]schema.Attribute{
+		"cert_secret_name": schema.StringAttribute{
+			Description: "Name of an existing secret containing the TLS certificates used to connect to the registry.",
+			Optional:    true,
+			Validators: []validator.String{
+				stringvalidator.RegexMatches(regexp.MustCompile(rfc1123DomainRegex), rfc1123DomainError),
+				stringvalidator.LengthAtMost(253),
+			},
+		},
+		"digest": schema.StringAttribute{
+			Description: "Digest of the OCI artifact to sync from. Conflicts with `semver` and `tag`.",
+			Optional:    true,
+			Validators: []validator.String{
+				stringvalidator.ConflictsWith(path.MatchRoot("semver"), path.MatchRoot("tag")),
+			},
+		},
+		"id": schema.StringAttribute{
+			Computed: true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"insecure": schema.BoolAttribute{
+			Description: "Allow connecting to an insecure (HTTP) container registry. Defaults to `false`.",
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+		},
+		"interval": schema.StringAttribute{
+			CustomType:  customtypes.DurationType{},
+			Description: fmt.Sprintf("Interval at which to reconcile from the OCI repository. Defaults to `%s`.", time.Minute.String()),
+			Optional:    true,
+			Computed:    true,
+			Default:     stringdefault.StaticString(time.Minute.String()),
+		},
+		"inventory": schema.MapAttribute{
+			ElementType: types.StringType,
+			Description: "Kubernetes objects managed by the provider mapped to the checksum of their desired state.",
+			Computed:    true,
+		},
+		"keep_namespace": schema.BoolAttribute{
+			Description: "Keep the namespace after uninstalling Flux components. Defaults to `false`.",
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+		},
+		"password": schema.StringAttribute{
+			Description: "Password used to pull the OCI artifact from the registry.",
+			Optional:    true,
+			Sensitive:   true,
+		},
+		"path": schema.StringAttribute{
+			Description: "Path relative to the root of the OCI artifact, when specified the cluster sync will be scoped to this path.",
+			Optional:    true,
+		},
+		"provider": schema.StringAttribute{
+			Description: fmt.Sprintf("The OIDC provider used to authenticate to the registry. Defaults to `%s`.", sourcev1.GenericOCIProvider),
+			Optional:    true,
+			Computed:    true,
+			Default:     stringdefault.StaticString(sourcev1.GenericOCIProvider),
+			Validators: []validator.String{
+				stringvalidator.OneOf(sourcev1.GenericOCIProvider, sourcev1.AmazonOCIProvider, sourcev1.AzureOCIProvider, sourcev1.GoogleOCIProvider),
+			},
+		},
+		"secret_name": schema.StringAttribute{
+			Description: fmt.Sprintf("Name of the secret the registry credentials are stored to. Defaults to `%s`.", defaultOpts.Namespace),
+			Optional:    true,
+			Computed:    true,
+			Default:     stringdefault.StaticString(defaultOpts.Namespace),
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+			Validators: []validator.String{
+				stringvalidator.RegexMatches(regexp.MustCompile(rfc1123DomainRegex), rfc1123DomainError),
+				stringvalidator.LengthAtMost(253),
+			},
+		},
+		"semver": schema.StringAttribute{
+			Description: "Semver range used to select the OCI artifact tag to sync from. Conflicts with `tag`.",
+			Optional:    true,
+			Validators: []validator.String{
+				stringvalidator.ConflictsWith(path.MatchRoot("tag")),
+			},
+		},
+		"tag": schema.StringAttribute{
+			Description: fmt.Sprintf("Tag of the OCI artifact to sync from. Defaults to `%s` when neither `digest` nor `semver` are set.", defaultOCITag),
+			Optional:    true,
+		},
+		"timeouts": timeouts.AttributesAll(ctx),
+		"url": schema.StringAttribute{
+			CustomType:  customtypes.URLType{},
+			Description: "URL of the OCI repository to sync the cluster from, in the format `oci://<host>/<repository>`.",
+			Required:    true,
+			Validators: []validator.String{
+				validators.URLScheme("oci"),
+			},
+		},
+		"username": schema.StringAttribute{
+			Description: "Username used to pull the OCI artifact from the registry.",
+			Optional:    true,
+		},
+	})
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Installs the Flux components in a Kubernetes cluster and configures the cluster to synchronize with an OCI repository.",
+		Attributes:          attributes,
+	}
+}
+
+func (r *bootstrapOCIResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data bootstrapOCIResourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateInstallOptions(data.installOptionsData)...)
+
+	if data.Username.IsUnknown() || data.Password.IsUnknown() || data.Provider.IsUnknown() {
+		return
+	}
+	if data.Username.IsNull() != data.Password.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("username"),
+			"Incomplete registry credentials",
+			"Both username and password must be set to authenticate to the registry.",
+		)
+	}
+	if !data.Username.IsNull() && !data.Provider.IsNull() && data.Provider.ValueString() != sourcev1.GenericOCIProvider {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("provider"),
+			"Conflicting registry authentication",
+			fmt.Sprintf("The username and password can only be set when provider is %s.", sourcev1.GenericOCIProvider),
+		)
+	}
+}
+
+// ModifyPlan sets the desired inventory of Kubernetes objects managed by the provider.
+func (r *bootstrapOCIResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// The inventory does not depend on the cluster, the provider configuration may be unknown until applied.
+	if r.prd == nil || (r.prd.rcg == nil && !r.prd.unknown) {
+		resp.Diagnostics.AddError(missingConfiguration, bootstrapOCIResourceMissingConfigError)
+		return
+	}
+
+	// Skip when deleting or on initial creation.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data bootstrapOCIResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	objects, err := getBootstrapOCIObjects(data)
+	if err != nil {
+		resp.Diagnostics.AddError("Getting expected bootstrap objects", err.Error())
+		return
+	}
+	inventory, err := getInventory(objects)
+	if err != nil {
+		resp.Diagnostics.AddError("Getting expected inventory", err.Error())
+		return
+	}
+	mapValue, diags := types.MapValueFrom(ctx, types.StringType, inventory)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Inventory = mapValue
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &data)...)
+}
+
+// Create installs the Flux controllers on the cluster and configures Flux to sync the cluster
+// state with the given OCI repository.
+func (r *bootstrapOCIResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.prd == nil || r.prd.rcg == nil {
+		resp.Diagnostics.AddError(missingConfiguration, bootstrapOCIResourceMissingConfigError)
+		return
+	}
+
+	var data bootstrapOCIResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	objects, err := getBootstrapOCIObjects(data)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not get bootstrap objects", err.Error())
+		return
+	}
+	if err := applyObjects(ctx, r.prd.rcg, r.prd.clientOpts, objects, nil, timeout); err != nil {
+		resp.Diagnostics.AddError("Bootstrap run error", err.Error())
+		return
+	}
+
+	inventory, err := getInventory(objects)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not get inventory", err.Error())
+		return
+	}
+	mapValue, diags := types.MapValueFrom(ctx, types.StringType, inventory)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Inventory = mapValue
+
+	data.ID = data.Namespace
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read detects drift of the Flux objects in the cluster and checks the readiness of the OCIRepository
+// and Kustomization. Drifted or failing objects are reset in the inventory which will trigger an update.
+func (r *bootstrapOCIResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.prd == nil || r.prd.rcg == nil {
+		resp.Diagnostics.AddError(missingConfiguration, bootstrapOCIResourceMissingConfigError)
+		return
+	}
+
+	var data bootstrapOCIResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Read(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	kubeClient, err := r.prd.GetKubernetesClient()
+	if err != nil {
+		resp.Diagnostics.AddError("Kubernetes Client", err.Error())
+		return
+	}
+
+	// Check cluster access and kubeconfig permissions
+	if err := isKubernetesReady(ctx, kubeClient); err != nil {
+		resp.Diagnostics.AddError("Kubernetes cluster", err.Error())
+		return
+	}
+
+	objects, err := getBootstrapOCIObjects(data)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not get bootstrap objects", err.Error())
+		return
+	}
+	inventory, err := getInventory(objects)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not get inventory", err.Error())
+		return
+	}
+
+	// Detect drift for the Flux installation in the cluster.
+	drifted, err := detectDrift(ctx, r.prd.rcg, r.prd.clientOpts, objects, inventory)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not detect drift", err.Error())
+		return
+	}
+	if len(drifted) > 0 {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("Flux components in %s namespace have drifted and will be reinstalled", data.Namespace.ValueString()),
+			strings.Join(drifted, "\n"),
+		)
+	}
+
+	ready, err := isFluxReady(ctx, kubeClient, data.Namespace.ValueString(), &sourcev1.OCIRepository{})
+	if !ready {
+		// Reset the OCIRepository checksum to simulate a drift which will trigger a redeployment.
+		for _, obj := range objects {
+			if obj.GetKind() == sourcev1.OCIRepositoryKind {
+				inventory[utils.ObjectKey(obj)] = ""
+			}
+		}
+		warnDetails := ""
+		if err != nil {
+			warnDetails = err.Error()
+		}
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("Flux resources OCIRepository and Kustomization in %s namespace are not ready and Flux will be redeployed", data.Namespace.ValueString()),
+			warnDetails,
+		)
+	}
+
+	mapValue, diags := types.MapValueFrom(ctx, types.StringType, inventory)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Inventory = mapValue
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update applies the changed Flux objects and removes objects that are no longer managed.
+func (r *bootstrapOCIResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.prd == nil || r.prd.rcg == nil {
+		resp.Diagnostics.AddError(missingConfiguration, bootstrapOCIResourceMissingConfigError)
+		return
+	}
+
+	var data bootstrapOCIResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var previous bootstrapOCIResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &previous)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	objects, err := getBootstrapOCIObjects(data)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not get bootstrap objects", err.Error())
+		return
+	}
+	previousObjects, err := getBootstrapOCIObjects(previous)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not get previous bootstrap objects", err.Error())
+		return
+	}
+	if err := applyObjects(ctx, r.prd.rcg, r.prd.clientOpts, objects, previousObjects, timeout); err != nil {
+		resp.Diagnostics.AddError("Bootstrap run error", err.Error())
+		return
+	}
+
+	inventory, err := getInventory(objects)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not get inventory", err.Error())
+		return
+	}
+	mapValue, diags := types.MapValueFrom(ctx, types.StringType, inventory)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Inventory = mapValue
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete removes the Flux components from the cluster.
+func (r *bootstrapOCIResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.prd == nil || r.prd.rcg == nil {
+		resp.Diagnostics.AddError(missingConfiguration, bootstrapOCIResourceMissingConfigError)
+		return
+	}
+
+	var data bootstrapOCIResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	kubeClient, err := r.prd.GetKubernetesClient()
+	if err != nil {
+		resp.Diagnostics.AddError("Kubernetes Client", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(uninstallFlux(ctx, kubeClient, data.Namespace.ValueString(), data.KeepNamespace.ValueBool())...)
+}
+
+// getBootstrapOCIObjects returns the install objects together with the registry pull secret,
+// the root OCIRepository and the root Kustomization.
+func getBootstrapOCIObjects(data bootstrapOCIResourceData) ([]*unstructured.Unstructured, error) {
+	objects, err := getInstallObjects(data.installOptionsData)
+	if err != nil {
+		return nil, err
+	}
+
+	ns := data.Namespace.ValueString()
+	ociRepository := &sourcev1.OCIRepository{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: sourcev1.GroupVersion.String(),
+			Kind:       sourcev1.OCIRepositoryKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ns,
+			Namespace: ns,
+		},
+		Spec: sourcev1.OCIRepositorySpec{
+			URL:       data.Url.ValueURL().String(),
+			Reference: getOCIRepositoryRef(data),
+			Provider:  data.Provider.ValueString(),
+			Interval:  metav1.Duration{Duration: data.Interval.ValueDuration()},
+			Insecure:  data.Insecure.ValueBool(),
+		},
+	}
+	if data.CertSecretName.ValueString() != "" {
+		ociRepository.Spec.CertSecretRef = &meta.LocalObjectReference{Name: data.CertSecretName.ValueString()}
+	}
+	if data.Username.ValueString() != "" {
+		ociRepository.Spec.SecretRef = &meta.LocalObjectReference{Name: data.SecretName.ValueString()}
+		secret, err := getDockerConfigSecret(data.SecretName.ValueString(), ns, data.Url.ValueURL().Host, data.Username.ValueString(), data.Password.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, secret)
+	}
+	ociRepositoryObj, err := toUnstructured(ociRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	kustomization := &kustomizev1.Kustomization{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kustomizev1.GroupVersion.String(),
+			Kind:       kustomizev1.KustomizationKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ns,
+			Namespace: ns,
+		},
+		Spec: kustomizev1.KustomizationSpec{
+			Interval: metav1.Duration{Duration: data.Interval.ValueDuration()},
+			Path:     fmt.Sprintf("./%s", strings.TrimPrefix(data.Path.ValueString(), "./")),
+			Prune:    true,
+			SourceRef: kustomizev1.CrossNamespaceSourceReference{
+				Kind: sourcev1.OCIRepositoryKind,
+				Name: ns,
+			},
+		},
+	}
+	kustomizationObj, err := toUnstructured(kustomization)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(objects, ociRepositoryObj, kustomizationObj), nil
+}
+
+func getOCIRepositoryRef(data bootstrapOCIResourceData) *sourcev1.OCIRepositoryRef {
+	switch {
+	case data.Digest.ValueString() != "":
+		return &sourcev1.OCIRepositoryRef{Digest: data.Digest.ValueString()}
+	case data.Semver.ValueString() != "":
+		return &sourcev1.OCIRepositoryRef{SemVer: data.Semver.ValueString()}
+	case data.Tag.ValueString() != "":
+		return &sourcev1.OCIRepositoryRef{Tag: data.Tag.ValueString()}
+	default:
+		return &sourcev1.OCIRepositoryRef{Tag: defaultOCITag}
+	}
+}
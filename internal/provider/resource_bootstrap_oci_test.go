@@ -0,0 +1,201 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+const registryImageName = "registry:2"
+
+func TestAccBootstrapOCI_Basic(t *testing.T) {
+	env := setupOCIEnvironment(t)
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: bootstrapOCI(env, "latest"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("flux_bootstrap_oci.this", "id", "flux-system"),
+					resource.TestCheckResourceAttrSet("flux_bootstrap_oci.this", "inventory.OCIRepository/flux-system/flux-system"),
+					resource.TestCheckResourceAttrSet("flux_bootstrap_oci.this", "inventory.Kustomization/flux-system/flux-system"),
+				),
+			},
+			// Change the artifact tag and expect the OCIRepository to be updated.
+			{
+				Config: bootstrapOCI(env, "v1.0.0"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("flux_bootstrap_oci.this", "tag", "v1.0.0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBootstrapOCI_InvalidURL(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "flux" {
+				  kubernetes = {
+				    config_path = "kubeconfig"
+				  }
+				}
+				resource "flux_bootstrap_oci" "this" {
+				  url = "https://example.com/flux-system"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Invalid URL scheme"),
+			},
+		},
+	})
+}
+
+func TestAccBootstrapOCI_MissingConfig(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "flux" {}
+				resource "flux_bootstrap_oci" "this" {
+				  url = "oci://example.com/flux-system"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Missing configuration"),
+			},
+		},
+	})
+}
+
+func bootstrapOCI(env ociEnvironment, tag string) string {
+	return fmt.Sprintf(`
+    provider "flux" {
+	  kubernetes = {
+        config_path = "%s"
+	  }
+    }
+
+    resource "flux_bootstrap_oci" "this" {
+		url      = "%s"
+		tag      = "%s"
+		insecure = true
+	}
+	`, env.kubeCfgPath, env.ociURL, tag)
+}
+
+type ociEnvironment struct {
+	kubeCfgPath string
+	ociURL      string
+}
+
+// setupOCIEnvironment starts a registry reachable from a new kind cluster
+// and pushes an artifact containing a single ConfigMap with the tags latest and v1.0.0.
+func setupOCIEnvironment(t *testing.T) ociEnvironment {
+	t.Helper()
+
+	port := rand.Intn(65535-1024) + 1024
+	randSuffix := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
+	registryName := fmt.Sprintf("registry-%s", randSuffix)
+	tmpDir := t.TempDir()
+
+	// Run registry server.
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	require.NoError(t, err)
+	defer func() { _ = cli.Close() }()
+	reader, err := cli.ImagePull(context.TODO(), registryImageName, image.PullOptions{})
+	require.NoError(t, err)
+	defer func() { _ = reader.Close() }()
+	_, err = io.Copy(io.Discard, reader)
+	require.NoError(t, err)
+
+	portSet, portMap, err := nat.ParsePortSpecs([]string{fmt.Sprintf("127.0.0.1:%d:%d", port, port)})
+	require.NoError(t, err)
+	containerCfg := &container.Config{
+		Image:        registryImageName,
+		ExposedPorts: portSet,
+		Env: []string{
+			fmt.Sprintf("REGISTRY_HTTP_ADDR=0.0.0.0:%d", port),
+		},
+	}
+	hostCfg := &container.HostConfig{
+		PortBindings: portMap,
+	}
+	resp, err := cli.ContainerCreate(context.TODO(), containerCfg, hostCfg, nil, nil, registryName)
+	require.NoError(t, err)
+	err = cli.ContainerStart(context.TODO(), resp.ID, container.StartOptions{})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = cli.ContainerRemove(context.TODO(), resp.ID, container.RemoveOptions{Force: true})
+	})
+
+	// Start Kind cluster.
+	kubeCfgPath := filepath.Join(tmpDir, ".kube", "config")
+	p := cluster.NewProvider(cluster.ProviderWithDocker())
+	err = p.Create(randSuffix, cluster.CreateWithKubeconfigPath(kubeCfgPath))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = p.Delete(randSuffix, kubeCfgPath)
+	})
+	networks, err := cli.NetworkList(context.TODO(), network.ListOptions{Filters: filters.NewArgs(filters.Arg("name", "kind"))})
+	require.NoError(t, err)
+	require.Len(t, networks, 1)
+	err = cli.NetworkConnect(context.TODO(), networks[0].ID, resp.ID, nil)
+	require.NoError(t, err)
+
+	// Push the artifact through the port exposed on localhost.
+	img, err := crane.Image(map[string][]byte{
+		"configmap.yaml": []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: bootstrap-oci
+  namespace: default
+data:
+  foo: bar
+`),
+	})
+	require.NoError(t, err)
+	for _, tag := range []string{"latest", "v1.0.0"} {
+		err = crane.Push(img, fmt.Sprintf("localhost:%d/flux-system:%s", port, tag), crane.Insecure)
+		require.NoError(t, err)
+	}
+
+	return ociEnvironment{
+		kubeCfgPath: kubeCfgPath,
+		ociURL:      fmt.Sprintf("oci://%s:%d/flux-system", registryName, port),
+	}
+}
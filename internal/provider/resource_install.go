@@ -0,0 +1,357 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const installResourceMissingConfigError = "Kubernetes configuration not found"
+
+type installResourceData struct {
+	installOptionsData
+	ID            types.String   `tfsdk:"id"`
+	Inventory     types.Map      `tfsdk:"inventory"`
+	KeepNamespace types.Bool     `tfsdk:"keep_namespace"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &installResource{}
+var _ resource.ResourceWithConfigure = &installResource{}
+var _ resource.ResourceWithModifyPlan = &installResource{}
+var _ resource.ResourceWithValidateConfig = &installResource{}
+
+type installResource struct {
+	prd *providerResourceData
+}
+
+func NewInstallResource() resource.Resource {
+	return &installResource{}
+}
+
+func (r *installResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	prd, ok := req.ProviderData.(*providerResourceData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.prd = prd
+}
+
+func (r *installResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_install"
+}
+
+func (r *installResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes, diags := installSchemaAttributes(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	maps.Copy(attributes, map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Computed: true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"inventory": schema.MapAttribute{
+			ElementType: types.StringType,
+			Description: "Kubernetes objects managed by the provider mapped to the checksum of their desired state.",
+			Computed:    true,
+		},
+		"keep_namespace": schema.BoolAttribute{
+			Description: "Keep the namespace after uninstalling Flux components. Defaults to `false`.",
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+		},
+		"timeouts": timeouts.AttributesAll(ctx),
+	})
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Installs the Flux components in a Kubernetes cluster without configuring a Git repository to synchronize from.",
+		Attributes:          attributes,
+	}
+}
+
+func (r *installResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data installResourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateInstallOptions(data.installOptionsData)...)
+}
+
+// ModifyPlan sets the desired inventory of Kubernetes objects managed by the provider.
+func (r *installResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// The inventory does not depend on the cluster, the provider configuration may be unknown until applied.
+	if r.prd == nil || (r.prd.rcg == nil && !r.prd.unknown) {
+		resp.Diagnostics.AddError(missingConfiguration, installResourceMissingConfigError)
+		return
+	}
+
+	// Skip when deleting or on initial creation.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data installResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	objects, err := getInstallObjects(data.installOptionsData)
+	if err != nil {
+		resp.Diagnostics.AddError("Getting expected install objects", err.Error())
+		return
+	}
+	inventory, err := getInventory(objects)
+	if err != nil {
+		resp.Diagnostics.AddError("Getting expected inventory", err.Error())
+		return
+	}
+	mapValue, diags := types.MapValueFrom(ctx, types.StringType, inventory)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Inventory = mapValue
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &data)...)
+}
+
+// Create applies the Flux install manifests on the cluster and waits for the controllers to become ready.
+func (r *installResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.prd == nil || r.prd.rcg == nil {
+		resp.Diagnostics.AddError(missingConfiguration, installResourceMissingConfigError)
+		return
+	}
+
+	var data installResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	objects, err := getInstallObjects(data.installOptionsData)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not get install objects", err.Error())
+		return
+	}
+	if err := applyObjects(ctx, r.prd.rcg, r.prd.clientOpts, objects, nil, timeout); err != nil {
+		resp.Diagnostics.AddError("Could not install Flux", err.Error())
+		return
+	}
+
+	inventory, err := getInventory(objects)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not get inventory", err.Error())
+		return
+	}
+	mapValue, diags := types.MapValueFrom(ctx, types.StringType, inventory)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Inventory = mapValue
+
+	data.ID = data.Namespace
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read compares the objects in the cluster with the install manifests to detect drift.
+// Objects that have drifted are reset in the inventory which will trigger an update.
+func (r *installResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.prd == nil || r.prd.rcg == nil {
+		resp.Diagnostics.AddError(missingConfiguration, installResourceMissingConfigError)
+		return
+	}
+
+	var data installResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Read(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	kubeClient, err := r.prd.GetKubernetesClient()
+	if err != nil {
+		resp.Diagnostics.AddError("Kubernetes Client", err.Error())
+		return
+	}
+
+	// Check cluster access and kubeconfig permissions
+	if err := isKubernetesReady(ctx, kubeClient); err != nil {
+		resp.Diagnostics.AddError("Kubernetes cluster", err.Error())
+		return
+	}
+
+	objects, err := getInstallObjects(data.installOptionsData)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not get install objects", err.Error())
+		return
+	}
+	inventory, err := getInventory(objects)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not get inventory", err.Error())
+		return
+	}
+	// Detect drift for the Flux installation in the cluster.
+	drifted, err := detectDrift(ctx, r.prd.rcg, r.prd.clientOpts, objects, inventory)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not detect drift", err.Error())
+		return
+	}
+	if len(drifted) > 0 {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("Flux components in %s namespace have drifted and will be reinstalled", data.Namespace.ValueString()),
+			strings.Join(drifted, "\n"),
+		)
+	}
+
+	mapValue, diags := types.MapValueFrom(ctx, types.StringType, inventory)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Inventory = mapValue
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update applies the changed install manifests and removes objects that are no longer part of the installation.
+func (r *installResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.prd == nil || r.prd.rcg == nil {
+		resp.Diagnostics.AddError(missingConfiguration, installResourceMissingConfigError)
+		return
+	}
+
+	var data installResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var previous installResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &previous)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	objects, err := getInstallObjects(data.installOptionsData)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not get install objects", err.Error())
+		return
+	}
+	previousObjects, err := getInstallObjects(previous.installOptionsData)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not get previous install objects", err.Error())
+		return
+	}
+	if err := applyObjects(ctx, r.prd.rcg, r.prd.clientOpts, objects, previousObjects, timeout); err != nil {
+		resp.Diagnostics.AddError("Could not update Flux", err.Error())
+		return
+	}
+
+	inventory, err := getInventory(objects)
+	if err != nil {
+		resp.Diagnostics.AddError("Could not get inventory", err.Error())
+		return
+	}
+	mapValue, diags := types.MapValueFrom(ctx, types.StringType, inventory)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Inventory = mapValue
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete removes the Flux components from the cluster.
+func (r *installResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.prd == nil || r.prd.rcg == nil {
+		resp.Diagnostics.AddError(missingConfiguration, installResourceMissingConfigError)
+		return
+	}
+
+	var data installResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := data.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	kubeClient, err := r.prd.GetKubernetesClient()
+	if err != nil {
+		resp.Diagnostics.AddError("Kubernetes Client", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(uninstallFlux(ctx, kubeClient, data.Namespace.ValueString(), data.KeepNamespace.ValueBool())...)
+}
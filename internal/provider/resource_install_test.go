@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+)
+
+func TestAccInstall_Basic(t *testing.T) {
+	env := setupEnvironment(t)
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: installBasic(env, `["FooBar"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("flux_install.this", "id", "flux-system"),
+					resource.TestCheckResourceAttrSet("flux_install.this", "inventory.Deployment/flux-system/source-controller"),
+					resource.TestCheckResourceAttrSet("flux_install.this", "inventory.Deployment/flux-system/kustomize-controller"),
+				),
+			},
+			// Change the toleration keys and expect the controllers to be updated.
+			{
+				Config: installBasic(env, `["FooBar", "test"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("flux_install.this", "toleration_keys.#", "2"),
+					checkDeploymentTolerations(t, env, "kustomize-controller", "FooBar", "test"),
+				),
+			},
+			// Remove a controller in-cluster and expect Terraform to detect the drift.
+			{
+				PreConfig: func() {
+					deployment := &appsv1.Deployment{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "kustomize-controller",
+							Namespace: "flux-system",
+						},
+					}
+					if err := getTestKubeClient(t, env.kubeCfgPath).Delete(context.Background(), deployment); err != nil {
+						t.Fatalf("Can not delete deployment: %s", err)
+					}
+				},
+				Config:             installBasic(env, `["FooBar", "test"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// Expect Terraform to correct the drift.
+			{
+				Config: installBasic(env, `["FooBar", "test"]`),
+				Check: resource.ComposeTestCheckFunc(
+					checkDeploymentTolerations(t, env, "kustomize-controller", "FooBar", "test"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstall_MissingConfig(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				provider "flux" {}
+				resource "flux_install" "this" {}
+				`,
+				ExpectError: regexp.MustCompile("Missing configuration"),
+			},
+		},
+	})
+}
+
+func installBasic(env environment, tolerationKeys string) string {
+	return fmt.Sprintf(`
+    provider "flux" {
+	  kubernetes = {
+        config_path = "%s"
+	  }
+    }
+
+    resource "flux_install" "this" {
+		toleration_keys = %s
+	}
+	`, env.kubeCfgPath, tolerationKeys)
+}
+
+// checkDeploymentTolerations checks that the Deployment exists in the cluster and tolerates the keys.
+func checkDeploymentTolerations(t *testing.T, env environment, name string, keys ...string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		deployment := &appsv1.Deployment{}
+		if err := getTestKubeClient(t, env.kubeCfgPath).Get(context.Background(), apitypes.NamespacedName{Name: name, Namespace: "flux-system"}, deployment); err != nil {
+			return err
+		}
+		tolerated := []string{}
+		for _, toleration := range deployment.Spec.Template.Spec.Tolerations {
+			tolerated = append(tolerated, toleration.Key)
+		}
+		for _, key := range keys {
+			if !slices.Contains(tolerated, key) {
+				return fmt.Errorf("expected %s to tolerate %s, got %v", name, key, tolerated)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/fluxcd/flux2/v2/pkg/manifestgen/sync"
+)
+
+// syncOptionsData holds the values used to render the Flux sync manifests. It is filled from the
+// attributes of every resource and data source that generates gotk-sync.yaml.
+type syncOptionsData struct {
+	Branch            string
+	Interval          time.Duration
+	Namespace         string
+	Path              string
+	RecurseSubmodules bool
+	SecretName        string
+	URL               *url.URL
+}
+
+func getSyncOptions(data syncOptionsData) sync.Options {
+	return sync.Options{
+		Branch:            data.Branch,
+		Interval:          data.Interval,
+		ManifestFile:      sync.MakeDefaultOptions().ManifestFile,
+		Name:              data.Namespace,
+		Namespace:         data.Namespace,
+		RecurseSubmodules: data.RecurseSubmodules,
+		Secret:            data.SecretName,
+		TargetPath:        data.Path,
+		URL:               data.URL.String(),
+	}
+}
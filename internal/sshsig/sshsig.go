@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sshsig creates SSH signatures as described in
+// https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig,
+// which Git uses for commits signed with gpg.format set to ssh.
+package sshsig
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	magicPreamble = "SSHSIG"
+	sigVersion    = 1
+	hashAlgorithm = "sha512"
+
+	// NamespaceGit is the namespace of commit and tag signatures.
+	NamespaceGit = "git"
+
+	armorStart = "-----BEGIN SSH SIGNATURE-----"
+	armorEnd   = "-----END SSH SIGNATURE-----"
+	lineLength = 70
+)
+
+// Signer signs messages with an SSH private key. It implements the Signer interface of go-git.
+type Signer struct {
+	signer    ssh.Signer
+	namespace string
+}
+
+// NewSigner parses the PEM encoded private key, which is decrypted with the passphrase when not empty.
+func NewSigner(privateKey, passphrase []byte, namespace string) (*Signer, error) {
+	var signer ssh.Signer
+	var err error
+	if len(passphrase) > 0 {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(privateKey, passphrase)
+	} else {
+		signer, err = ssh.ParsePrivateKey(privateKey)
+	}
+	if err != nil {
+		var missingErr *ssh.PassphraseMissingError
+		if errors.As(err, &missingErr) {
+			return nil, fmt.Errorf("private key is encrypted but no passphrase is set")
+		}
+		return nil, fmt.Errorf("could not parse private key: %w", err)
+	}
+	return &Signer{signer: signer, namespace: namespace}, nil
+}
+
+// PublicKey returns the public key of the signer.
+func (s *Signer) PublicKey() ssh.PublicKey {
+	return s.signer.PublicKey()
+}
+
+// Sign returns the armored signature of the message.
+func (s *Signer) Sign(message io.Reader) ([]byte, error) {
+	h := sha512.New()
+	if _, err := io.Copy(h, message); err != nil {
+		return nil, err
+	}
+	signedData := append([]byte(magicPreamble), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{s.namespace, "", hashAlgorithm, h.Sum(nil)})...)
+
+	var sig *ssh.Signature
+	var err error
+	// RSA keys have to sign with SHA-2, as ssh-rsa signatures are rejected by OpenSSH.
+	if as, ok := s.signer.(ssh.AlgorithmSigner); ok && s.signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		sig, err = as.SignWithAlgorithm(rand.Reader, signedData, ssh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = s.signer.Sign(rand.Reader, signedData)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not sign message: %w", err)
+	}
+
+	blob := append([]byte(magicPreamble), ssh.Marshal(struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}{sigVersion, s.signer.PublicKey().Marshal(), s.namespace, "", hashAlgorithm, ssh.Marshal(sig)})...)
+	return armor(blob), nil
+}
+
+func armor(blob []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(blob)
+	var b bytes.Buffer
+	b.WriteString(armorStart + "\n")
+	for len(encoded) > lineLength {
+		b.WriteString(encoded[:lineLength] + "\n")
+		encoded = encoded[lineLength:]
+	}
+	b.WriteString(encoded + "\n")
+	b.WriteString(armorEnd + "\n")
+	return b.Bytes()
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshsig
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSign(t *testing.T) {
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		key           crypto.PrivateKey
+		passphrase    string
+		wantAlgorithm string
+	}{
+		{name: "ed25519", key: ed25519Key, wantAlgorithm: ssh.KeyAlgoED25519},
+		{name: "ed25519 with passphrase", key: ed25519Key, passphrase: "secret", wantAlgorithm: ssh.KeyAlgoED25519},
+		{name: "rsa", key: rsaKey, wantAlgorithm: ssh.KeyAlgoRSASHA512},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var block *pem.Block
+			if tt.passphrase != "" {
+				block, err = ssh.MarshalPrivateKeyWithPassphrase(tt.key, "", []byte(tt.passphrase))
+			} else {
+				block, err = ssh.MarshalPrivateKey(tt.key, "")
+			}
+			require.NoError(t, err)
+			signer, err := NewSigner(pem.EncodeToMemory(block), []byte(tt.passphrase), NamespaceGit)
+			require.NoError(t, err)
+
+			message := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\nInit Flux\n"
+			armored, err := signer.Sign(strings.NewReader(message))
+			require.NoError(t, err)
+
+			lines := strings.Split(strings.TrimSuffix(string(armored), "\n"), "\n")
+			require.Equal(t, armorStart, lines[0])
+			require.Equal(t, armorEnd, lines[len(lines)-1])
+			for _, l := range lines[1 : len(lines)-1] {
+				assert.LessOrEqual(t, len(l), lineLength)
+			}
+			blob, err := base64.StdEncoding.DecodeString(strings.Join(lines[1:len(lines)-1], ""))
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(string(blob), magicPreamble))
+
+			var sigBlob struct {
+				Version       uint32
+				PublicKey     []byte
+				Namespace     string
+				Reserved      string
+				HashAlgorithm string
+				Signature     []byte
+			}
+			require.NoError(t, ssh.Unmarshal(blob[len(magicPreamble):], &sigBlob))
+			assert.Equal(t, uint32(sigVersion), sigBlob.Version)
+			assert.Equal(t, NamespaceGit, sigBlob.Namespace)
+			assert.Equal(t, hashAlgorithm, sigBlob.HashAlgorithm)
+			assert.Equal(t, signer.PublicKey().Marshal(), sigBlob.PublicKey)
+
+			var sig ssh.Signature
+			require.NoError(t, ssh.Unmarshal(sigBlob.Signature, &sig))
+			assert.Equal(t, tt.wantAlgorithm, sig.Format)
+
+			hash := sha512.Sum512([]byte(message))
+			signedData := append([]byte(magicPreamble), ssh.Marshal(struct {
+				Namespace     string
+				Reserved      string
+				HashAlgorithm string
+				Hash          []byte
+			}{NamespaceGit, "", hashAlgorithm, hash[:]})...)
+			assert.NoError(t, signer.PublicKey().Verify(signedData, &sig))
+		})
+	}
+}
+
+func TestNewSignerEncryptedKey(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte("secret"))
+	require.NoError(t, err)
+
+	_, err = NewSigner(pem.EncodeToMemory(block), nil, NamespaceGit)
+	assert.ErrorContains(t, err, "no passphrase is set")
+	_, err = NewSigner(pem.EncodeToMemory(block), []byte("wrong"), NamespaceGit)
+	assert.Error(t, err)
+}
@@ -20,30 +20,29 @@ import (
 	"bytes"
 	"fmt"
 	"path"
+	"strings"
 	"text/template"
+
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
 )
 
 type KustomizationValues struct {
 	Paths   []string
-	Patches []string
+	Patches []kustypes.Patch
+	Images  []kustypes.Image
 }
 
-func GenerateKustomizationYaml(paths []string, patches []string) (string, error) {
-	var t *template.Template
-	var err error
-
-	if len(patches) == 0 {
-		t, err = template.New("kustomize").Parse(kustomizeTemplateString)
-	} else {
-		t, err = template.New("kustomize").Parse(kustomizeWithPatchesTemplateString)
-	}
-
+// GenerateKustomizationYaml returns a kustomization of the resources with the patches and image overrides.
+// Patches either refer to a file, e.g. one of GenPatchFilePaths, or contain the patch inline.
+func GenerateKustomizationYaml(paths []string, patches []kustypes.Patch, images []kustypes.Image) (string, error) {
+	t, err := template.New("kustomize").Funcs(template.FuncMap{"toYaml": toYaml}).Parse(kustomizeTemplateString)
 	if err != nil {
 		return "", err
 	}
 
 	var kustomize bytes.Buffer
-	values := KustomizationValues{paths, patches}
+	values := KustomizationValues{paths, patches, images}
 	err = t.Execute(&kustomize, values)
 	if err != nil {
 		return "", err
@@ -52,6 +51,14 @@ func GenerateKustomizationYaml(paths []string, patches []string) (string, error)
 	return kustomize.String(), nil
 }
 
+func toYaml(v interface{}) (string, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
 const kustomizeTemplateString = `
 apiVersion: kustomize.config.k8s.io/v1beta1
 kind: Kustomization
@@ -59,18 +66,13 @@ resources:
 {{- range .Paths }}
 - {{.}}
 {{- end }}
-`
-
-const kustomizeWithPatchesTemplateString = `
-apiVersion: kustomize.config.k8s.io/v1beta1
-kind: Kustomization
-resources:
-{{- range .Paths }}
-- {{.}}
+{{- if .Patches }}
+patches:
+{{ toYaml .Patches }}
 {{- end }}
-patchesStrategicMerge:
-{{- range .Patches }}
-- {{.}}
+{{- if .Images }}
+images:
+{{ toYaml .Images }}
 {{- end }}
 `
 
@@ -21,10 +21,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/resid"
 )
 
 func TestGenereateKustomizationYamlWithNoPatches(t *testing.T) {
-	result, err := GenerateKustomizationYaml([]string{"foo", "bar"}, []string{})
+	result, err := GenerateKustomizationYaml([]string{"foo", "bar"}, nil, nil)
 
 	expected := `
 apiVersion: kustomize.config.k8s.io/v1beta1
@@ -39,7 +41,17 @@ resources:
 }
 
 func TestGenereateKustomizationYamlWithPatches(t *testing.T) {
-	result, err := GenerateKustomizationYaml([]string{"foo", "bar"}, []string{"baz", "buzz"})
+	patches := []kustypes.Patch{
+		{Path: "patch-baz.yaml"},
+		{
+			Patch: "- op: add\n  path: /spec/replicas\n  value: 2\n",
+			Target: &kustypes.Selector{
+				ResId:         resid.ResId{Gvk: resid.Gvk{Kind: "Deployment"}, Name: "buzz"},
+				LabelSelector: "app=buzz",
+			},
+		},
+	}
+	result, err := GenerateKustomizationYaml([]string{"foo", "bar"}, patches, nil)
 
 	expected := `
 apiVersion: kustomize.config.k8s.io/v1beta1
@@ -47,9 +59,37 @@ kind: Kustomization
 resources:
 - foo
 - bar
-patchesStrategicMerge:
-- baz
-- buzz
+patches:
+- path: patch-baz.yaml
+- patch: |
+    - op: add
+      path: /spec/replicas
+      value: 2
+  target:
+    kind: Deployment
+    labelSelector: app=buzz
+    name: buzz
+`
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestGenereateKustomizationYamlWithImages(t *testing.T) {
+	images := []kustypes.Image{
+		{Name: "ghcr.io/fluxcd/source-controller", NewName: "registry.example.com/source-controller", NewTag: "v1.0.0"},
+	}
+	result, err := GenerateKustomizationYaml([]string{"foo"}, nil, images)
+
+	expected := `
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- foo
+images:
+- name: ghcr.io/fluxcd/source-controller
+  newName: registry.example.com/source-controller
+  newTag: v1.0.0
 `
 
 	assert.Nil(t, err)
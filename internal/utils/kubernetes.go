@@ -114,18 +114,31 @@ func KubeClient(rcg genericclioptions.RESTClientGetter, opts *runclient.Options)
 
 type RESTClientGetter struct {
 	clientconfig clientcmd.ClientConfig
+	opts         *runclient.Options
 }
 
-func NewRestClientGetter(clientconfig clientcmd.ClientConfig) *RESTClientGetter {
-	return &RESTClientGetter{clientconfig: clientconfig}
+// NewRestClientGetter returns a RESTClientGetter for the client configuration. The QPS and Burst of the
+// options are set on the returned REST configurations when not zero, otherwise the client-go defaults apply.
+func NewRestClientGetter(clientconfig clientcmd.ClientConfig, opts *runclient.Options) *RESTClientGetter {
+	return &RESTClientGetter{clientconfig: clientconfig, opts: opts}
 }
 
 func (r *RESTClientGetter) ToRESTConfig() (*rest.Config, error) {
-	return r.clientconfig.ClientConfig()
+	cfg, err := r.clientconfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	if r.opts != nil && r.opts.QPS > 0 {
+		cfg.QPS = r.opts.QPS
+	}
+	if r.opts != nil && r.opts.Burst > 0 {
+		cfg.Burst = r.opts.Burst
+	}
+	return cfg, nil
 }
 
 func (r *RESTClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
-	restconfig, err := r.clientconfig.ClientConfig()
+	restconfig, err := r.ToRESTConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -20,10 +20,49 @@ import (
 	"fmt"
 	"testing"
 
+	runclient "github.com/fluxcd/pkg/runtime/client"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
+func TestRESTClientGetter(t *testing.T) {
+	config := clientcmdapi.NewConfig()
+	config.Clusters["test"] = &clientcmdapi.Cluster{Server: "https://127.0.0.1:6443"}
+	config.AuthInfos["test"] = &clientcmdapi.AuthInfo{}
+	config.Contexts["test"] = &clientcmdapi.Context{Cluster: "test", AuthInfo: "test"}
+	config.CurrentContext = "test"
+	clientConfig := clientcmd.NewNonInteractiveClientConfig(*config, "test", &clientcmd.ConfigOverrides{Timeout: "30s"}, nil)
+
+	tests := []struct {
+		name          string
+		opts          *runclient.Options
+		expectedQPS   float32
+		expectedBurst int
+	}{
+		{
+			name: "client-go defaults",
+			opts: &runclient.Options{},
+		},
+		{
+			name:          "options set",
+			opts:          &runclient.Options{QPS: 50, Burst: 100},
+			expectedQPS:   50,
+			expectedBurst: 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := NewRestClientGetter(clientConfig, tt.opts).ToRESTConfig()
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQPS, cfg.QPS)
+			require.Equal(t, tt.expectedBurst, cfg.Burst)
+			require.Equal(t, "30s", cfg.Timeout.String())
+		})
+	}
+}
+
 func TestGetContainers(t *testing.T) {
 	containers := []corev1.Container{
 		{
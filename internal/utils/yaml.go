@@ -0,0 +1,216 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// SplitYAMLDocuments splits a multi-document YAML string into its documents.
+// Empty documents and documents only containing comments are skipped.
+func SplitYAMLDocuments(content string) ([]string, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(content)))
+	docs := []string{}
+	for {
+		b, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isEmptyYAMLDocument(string(b)) {
+			continue
+		}
+		docs = append(docs, strings.TrimPrefix(string(b), "---\n"))
+	}
+	return docs, nil
+}
+
+func isEmptyYAMLDocument(doc string) bool {
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "---" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// ReadObjects parses a multi-document YAML string into Kubernetes objects.
+func ReadObjects(content string) ([]*unstructured.Unstructured, error) {
+	docs, err := SplitYAMLDocuments(content)
+	if err != nil {
+		return nil, err
+	}
+	objects := []*unstructured.Unstructured{}
+	for _, doc := range docs {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(doc), &obj.Object); err != nil {
+			return nil, fmt.Errorf("could not parse object: %w", err)
+		}
+		if obj.Object == nil {
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// ObjectKey returns a human readable identifier of the object in the format
+// Kind/namespace/name, or Kind/name for cluster scoped objects.
+func ObjectKey(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())
+	}
+	return fmt.Sprintf("%s/%s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+// DiffObjects compares the Kubernetes objects of two multi-document YAML strings. A description is
+// returned for each expected object which is missing or has changed fields, and for each unexpected object.
+func DiffObjects(actual, expected string) ([]string, error) {
+	actualObjects, err := ReadObjects(actual)
+	if err != nil {
+		return nil, err
+	}
+	expectedObjects, err := ReadObjects(expected)
+	if err != nil {
+		return nil, err
+	}
+
+	unexpected := map[string]*unstructured.Unstructured{}
+	for _, obj := range actualObjects {
+		unexpected[ObjectKey(obj)] = obj
+	}
+	diffs := []string{}
+	for _, obj := range expectedObjects {
+		key := ObjectKey(obj)
+		actualObj, ok := unexpected[key]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s is missing", key))
+			continue
+		}
+		delete(unexpected, key)
+		if fields := diffFields("", actualObj.Object, obj.Object); len(fields) > 0 {
+			diffs = append(diffs, fmt.Sprintf("%s changed %s", key, strings.Join(fields, ", ")))
+		}
+	}
+	for key := range unexpected {
+		diffs = append(diffs, fmt.Sprintf("%s is not expected", key))
+	}
+	sort.Strings(diffs)
+	return diffs, nil
+}
+
+// diffFields returns the paths of the fields which differ between the values. List items are
+// matched by their name when all items have one, e.g. containers[manager].image.
+func diffFields(fieldPath string, actual, expected interface{}) []string {
+	switch expected := expected.(type) {
+	case map[string]interface{}:
+		actual, ok := actual.(map[string]interface{})
+		if !ok {
+			return []string{fieldPath}
+		}
+		keys := []string{}
+		for k := range expected {
+			keys = append(keys, k)
+		}
+		for k := range actual {
+			if _, ok := expected[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		fields := []string{}
+		for _, k := range keys {
+			p := k
+			if fieldPath != "" {
+				p = fieldPath + "." + k
+			}
+			fields = append(fields, diffFields(p, actual[k], expected[k])...)
+		}
+		return fields
+	case []interface{}:
+		actual, ok := actual.([]interface{})
+		if !ok {
+			return []string{fieldPath}
+		}
+		actualNamed, actualOk := namedItems(actual)
+		expectedNamed, expectedOk := namedItems(expected)
+		if actualOk && expectedOk {
+			names := []string{}
+			for name := range expectedNamed {
+				names = append(names, name)
+			}
+			for name := range actualNamed {
+				if _, ok := expectedNamed[name]; !ok {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			fields := []string{}
+			for _, name := range names {
+				fields = append(fields, diffFields(fmt.Sprintf("%s[%s]", fieldPath, name), actualNamed[name], expectedNamed[name])...)
+			}
+			return fields
+		}
+		if len(actual) != len(expected) {
+			return []string{fieldPath}
+		}
+		fields := []string{}
+		for i := range expected {
+			fields = append(fields, diffFields(fmt.Sprintf("%s[%d]", fieldPath, i), actual[i], expected[i])...)
+		}
+		return fields
+	default:
+		if !reflect.DeepEqual(actual, expected) {
+			return []string{fieldPath}
+		}
+		return nil
+	}
+}
+
+// namedItems returns the list items by their name, if all items are objects with a unique name.
+func namedItems(items []interface{}) (map[string]interface{}, bool) {
+	named := map[string]interface{}{}
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, ok := m["name"].(string)
+		if !ok {
+			return nil, false
+		}
+		if _, ok := named[name]; ok {
+			return nil, false
+		}
+		named[name] = item
+	}
+	return named, true
+}
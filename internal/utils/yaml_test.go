@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitYAMLDocuments(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			name:     "single document",
+			content:  "kind: Namespace\nmetadata:\n  name: flux-system\n",
+			expected: []string{"kind: Namespace\nmetadata:\n  name: flux-system\n"},
+		},
+		{
+			name:     "multiple documents",
+			content:  "---\nkind: Namespace\n---\nkind: ServiceAccount\n",
+			expected: []string{"kind: Namespace\n", "kind: ServiceAccount\n"},
+		},
+		{
+			name:     "skip empty and comment documents",
+			content:  "# This manifest was generated by flux.\n---\n\n---\nkind: Namespace\n",
+			expected: []string{"kind: Namespace\n"},
+		},
+		{
+			name:     "empty content",
+			content:  "",
+			expected: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := SplitYAMLDocuments(tt.content)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, docs)
+		})
+	}
+}
+
+func TestReadObjects(t *testing.T) {
+	content := `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: flux-system
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: source-controller
+  namespace: flux-system
+`
+	objects, err := ReadObjects(content)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	require.Equal(t, "Namespace/flux-system", ObjectKey(objects[0]))
+	require.Equal(t, "Deployment/flux-system/source-controller", ObjectKey(objects[1]))
+	require.Equal(t, "apps/v1", objects[1].GetAPIVersion())
+}
+
+func TestDiffObjects(t *testing.T) {
+	expected := `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: flux-system
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: kustomize-controller
+  namespace: flux-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: ghcr.io/fluxcd/kustomize-controller:v1.5.0
+        args:
+        - --watch-all-namespaces
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: source-controller
+  namespace: flux-system
+`
+	tests := []struct {
+		name     string
+		actual   string
+		expected []string
+	}{
+		{
+			name:     "unchanged with different formatting",
+			actual:   "# generated\n" + strings.ReplaceAll(expected, "- name: manager\n        image: ghcr.io/fluxcd/kustomize-controller:v1.5.0", "- image: ghcr.io/fluxcd/kustomize-controller:v1.5.0\n        name: manager"),
+			expected: []string{},
+		},
+		{
+			name:   "changed, missing and unexpected objects",
+			actual: strings.ReplaceAll(strings.ReplaceAll(expected, "v1.5.0", "v1.4.0"), "name: source-controller", "name: helm-controller"),
+			expected: []string{
+				"Deployment/flux-system/kustomize-controller changed spec.template.spec.containers[manager].image",
+				"ServiceAccount/flux-system/helm-controller is not expected",
+				"ServiceAccount/flux-system/source-controller is missing",
+			},
+		},
+		{
+			name:     "changed list",
+			actual:   strings.ReplaceAll(expected, "- --watch-all-namespaces", "- --watch-all-namespaces\n        - --log-level=debug"),
+			expected: []string{"Deployment/flux-system/kustomize-controller changed spec.template.spec.containers[manager].args"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffs, err := DiffObjects(tt.actual, expected)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, diffs)
+		})
+	}
+}